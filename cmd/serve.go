@@ -5,9 +5,15 @@ package cmd
 
 import (
 	"fmt"
+	"log"
+	"os"
 
 	"github.com/mark3labs/mcp-docker/internal/docker"
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/progress"
+	"github.com/mark3labs/mcp-docker/internal/stdio"
 	"github.com/mark3labs/mcp-docker/internal/trivy"
+	"github.com/mark3labs/mcp-docker/internal/workspace"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 )
@@ -23,13 +29,26 @@ Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		trivy.Configure(trivyConfig)
+		output.Configure(outputConfig)
+		// Errors go to stderr, stdout being the protocol channel.
+		if err := workspace.Configure(workspaceDir); err != nil {
+			fmt.Fprintln(os.Stderr, "Error starting server:", err)
+			os.Exit(1)
+		}
+
 		s := server.NewMCPServer(
 			"Calculator Demo Application",
 			"1.0.0",
 			server.WithResourceCapabilities(true, true),
 			server.WithPromptCapabilities(true),
 			server.WithLogging(),
+			// Errors are recorded first, to answer the calls failing on
+			// their arguments with invalid params errors.
+			server.WithToolHandlerMiddleware(stdio.RecordErrors),
 			server.WithRecovery(),
+			server.WithToolHandlerMiddleware(progress.Middleware),
+			server.WithToolHandlerMiddleware(output.Middleware),
 		)
 
 		docker.WithInspectTool(s)
@@ -40,19 +59,78 @@ to quickly create a Cobra application.`,
 		docker.WithRunTool(s)
 		docker.WithExecTool(s)
 		docker.WithSBOMTool(s)
+		docker.WithSBOMQueryTool(s)
+		docker.WithSBOMDiffTool(s)
+		docker.WithLicenseReportTool(s)
 		docker.WithImageTools(s)
 		docker.WithSearchTool(s)
 		docker.WithPullTool(s)
 		docker.WithAttachTool(s)
 		trivy.WithImageTool(s)
+		trivy.WithFSTools(s)
+		trivy.WithRepoTool(s)
+		trivy.WithContainerTool(s)
+		trivy.WithImageDiffTool(s)
+		trivy.WithGateTool(s)
+		trivy.WithDBStatusTool(s)
+		workspace.WithFileResources(s)
+		docker.WithResources(s)
+		docker.WithPrompts(s)
 
-		if err := server.ServeStdio(s); err != nil {
-			fmt.Println("Error starting server:", err)
+		// mcp-go doesn't handle resource subscriptions, so they're handled
+		// by the stdio server in front of it.
+		stdioServer := stdio.NewServer(s)
+		watcher := docker.NewContainerWatcher(func(uri string) {
+			if err := stdioServer.Notify("notifications/resources/updated", map[string]interface{}{"uri": uri}); err != nil {
+				log.Printf("Error notifying update of %s: %v", uri, err)
+			}
+		})
+		stdioServer.Handle("resources/subscribe", watcher.Subscribe)
+		stdioServer.Handle("resources/unsubscribe", watcher.Unsubscribe)
+
+		if err := stdio.ServeStdio(stdioServer); err != nil {
+			fmt.Fprintln(os.Stderr, "Error starting server:", err)
+			os.Exit(1)
 		}
 
 	},
 }
 
+// trivyConfig is the configuration of the trivy tools, set from the flags
+// of the serve command.
+var trivyConfig trivy.Config
+
+// workspaceDir is the directory the tools write files to.
+var workspaceDir string
+
+// outputConfig is the configuration of the tool results, set from the
+// flags of the serve command.
+var outputConfig output.Config
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&workspaceDir, "workspace", workspace.DefaultDir(),
+		"Directory the tools write files to, e.g. SBOMs and SARIF logs, paths outside of it are rejected")
+	serveCmd.Flags().IntVar(&outputConfig.MaxSize, "max-response-size", output.DefaultMaxSize,
+		"Max size in bytes of the text and of each embedded resource of a tool result, larger ones are stored in the workspace, 0 disables it")
+	serveCmd.Flags().DurationVar(&outputConfig.Retention, "output-retention", output.DefaultRetention,
+		"Time the outputs stored in the workspace are kept for, 0 keeps them forever")
+
+	serveCmd.Flags().StringVar(&trivyConfig.CacheDir, "trivy-cache-dir", "",
+		"Trivy cache directory, holding a pre-seeded vulnerability DB")
+	serveCmd.Flags().BoolVar(&trivyConfig.SkipDBUpdate, "trivy-skip-db-update", false,
+		"Scan with the vulnerability DB in the cache, without downloading it")
+	serveCmd.Flags().BoolVar(&trivyConfig.OfflineScan, "trivy-offline-scan", false,
+		"Avoid any request to the internet while scanning")
+	serveCmd.Flags().StringVar(&trivyConfig.DBRepository, "trivy-db-repository", "",
+		"OCI repository to download the trivy vulnerability DB from")
+	serveCmd.Flags().DurationVar(&trivyConfig.ResultTTL, "trivy-result-ttl", trivy.DefaultResultTTL,
+		"Time image scan results are cached for, 0 disables the cache")
+	serveCmd.Flags().StringVar(&trivyConfig.Server, "trivy-server", "",
+		"URL of a trivy server to delegate the scans to, e.g. http://trivy.lan:4954")
+	serveCmd.Flags().StringVar(&trivyConfig.Token, "trivy-token", os.Getenv("TRIVY_TOKEN"),
+		"Token authenticating against the trivy server, defaults to $TRIVY_TOKEN")
+	serveCmd.Flags().StringVar(&trivyConfig.TokenHeader, "trivy-token-header", "",
+		"HTTP header the token is sent in, trivy defaults to Trivy-Token")
 }
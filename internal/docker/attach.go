@@ -4,41 +4,252 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os/exec"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+const (
+	defaultAttachDuration = 5
+	defaultAttachMaxBytes = 64 * 1024
+	defaultDetachKeys     = "ctrl-p,ctrl-q"
+
+	// detachGracePeriod is how long we wait for the docker cli to exit
+	// after sending the detach sequence, before killing the client.
+	detachGracePeriod = 2 * time.Second
+)
+
 var AttachTool = mcp.NewTool("docker_attach",
-	mcp.WithDescription("Attach to a running container"),
+	mcp.WithDescription("Attach to a running container, capture its output for a bounded window and detach"),
 	mcp.WithString("containerID",
 		mcp.Required(),
 		mcp.Description("The ID of the container to attach to"),
+		params.Format(params.ContainerFormat),
+	),
+	mcp.WithNumber("duration",
+		mcp.Description("How many seconds to capture output before detaching"),
+		mcp.DefaultNumber(defaultAttachDuration),
+		mcp.Min(1),
+		mcp.Max(300),
+	),
+	mcp.WithNumber("max-bytes",
+		mcp.Description("Detach as soon as this many bytes of output were captured"),
+		mcp.DefaultNumber(defaultAttachMaxBytes),
+		mcp.Min(1),
+	),
+	mcp.WithString("input",
+		mcp.Description("Data to send to the container's stdin after attaching"),
+	),
+	mcp.WithString("detach-keys",
+		mcp.Description("The key sequence used to detach from the container"),
+		mcp.DefaultString(defaultDetachKeys),
 	),
+	output.StoreOption(),
 )
 
-// AttachHandler is the handler function that handles attach requests
+// AttachHandler is the handler function that handles attach requests.
+// Instead of blocking until the container exits, it captures the output
+// until the duration elapses or the byte limit is reached, and then
+// detaches from the container, leaving it running.
 func AttachHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Implement the logic to attach to a Docker container
-	// This is a placeholder implementation
-	containerID := req.Params.Arguments["containerID"].(string)
+	containerID, err := params.String(req, "containerID")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := params.Int(req, "duration", defaultAttachDuration)
+	if err != nil {
+		return nil, err
+	}
+	maxBytes, err := params.Int(req, "max-bytes", defaultAttachMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	input, err := params.OptionalString(req, "input", "")
+	if err != nil {
+		return nil, err
+	}
+	detachKeys, err := params.OptionalString(req, "detach-keys", defaultDetachKeys)
+	if err != nil {
+		return nil, err
+	}
+	detachSequence, err := parseDetachKeys(detachKeys)
+	if err != nil {
+		return nil, err
+	}
 
 	var stderr bytes.Buffer
-	diffCmd := exec.Command("docker", "attach", containerID)
-	diffCmd.Stderr = &stderr
-
-	outBytes, err := diffCmd.Output()
+	stateCmd := exec.Command("docker", "inspect", "--type", "container", "--format", "{{.State.Running}}", containerID)
+	stateCmd.Stderr = &stderr
+	stateBytes, err := stateCmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to attach to container %s: %w\n %s", containerID, err, stderr.String())
+		return nil, fmt.Errorf("failed to inspect container %s: %w\n %s", containerID, err, stderr.String())
+	}
+	if strings.TrimSpace(string(stateBytes)) != "true" {
+		return nil, fmt.Errorf("container %s is not running", containerID)
 	}
-	result := string(outBytes)
 
-	return mcp.NewToolResultText(fmt.Sprintf("%s", result)), nil
+	// The signal proxy is disabled, so killing the docker cli never
+	// forwards the signal to the container itself.
+	args := []string{"attach", "--sig-proxy=false", "--detach-keys", detachKeys}
+	if input == "" {
+		args = append(args, "--no-stdin")
+	}
+	args = append(args, containerID)
+
+	attachCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	captured := newCaptureBuffer(maxBytes)
+	attachCmd := exec.CommandContext(attachCtx, "docker", args...)
+	attachCmd.Stdout = captured
+	attachCmd.Stderr = captured
+
+	var stdin io.WriteCloser
+	if input != "" {
+		stdin, err = attachCmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to attach to container %s: %w", containerID, err)
+		}
+	}
+
+	if err := attachCmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to attach to container %s: %w", containerID, err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- attachCmd.Wait()
+	}()
+
+	if stdin != nil {
+		if _, err := io.WriteString(stdin, input); err != nil {
+			cancel()
+			<-done
+			return nil, fmt.Errorf("failed to send input to container %s: %w\n %s", containerID, err, captured.String())
+		}
+	}
+
+	timer := time.NewTimer(time.Duration(duration) * time.Second)
+	defer timer.Stop()
+
+	var reason string
+	exited := false
+	select {
+	case err := <-done:
+		exited = true
+		reason = "container exited"
+		if err != nil {
+			reason = fmt.Sprintf("attach ended: %v", err)
+		}
+	case <-timer.C:
+		reason = fmt.Sprintf("captured for %ds", duration)
+	case <-captured.Full():
+		reason = fmt.Sprintf("captured %d bytes", maxBytes)
+	case <-ctx.Done():
+		reason = "request cancelled"
+	}
+
+	if !exited {
+		detach(stdin, detachSequence, done, cancel)
+	}
+
+	result := fmt.Sprintf("%s\n[detached from container %s: %s]", captured.String(), containerID, reason)
+	return mcp.NewToolResultText(result), nil
+}
+
+// detach sends the detach sequence to the docker cli, if stdin is attached,
+// and waits for it to exit. When the cli does not exit in time it's killed,
+// which detaches the client while leaving the container running.
+func detach(stdin io.WriteCloser, sequence []byte, done <-chan error, cancel context.CancelFunc) {
+	if stdin != nil {
+		if _, err := stdin.Write(sequence); err == nil {
+			select {
+			case <-done:
+				return
+			case <-time.After(detachGracePeriod):
+			}
+		}
+	}
+	cancel()
+	<-done
+}
+
+// parseDetachKeys converts a detach key specification, such as
+// "ctrl-p,ctrl-q", into the byte sequence that has to be written to stdin.
+func parseDetachKeys(keys string) ([]byte, error) {
+	var sequence []byte
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		switch {
+		case len(key) == 1:
+			sequence = append(sequence, key[0])
+		case len(key) == 6 && strings.HasPrefix(strings.ToLower(key), "ctrl-"):
+			c := key[5]
+			switch {
+			case c >= 'a' && c <= 'z':
+				sequence = append(sequence, c-'a'+1)
+			case c >= '@' && c <= '_':
+				sequence = append(sequence, c-'@')
+			default:
+				return nil, fmt.Errorf("%w: invalid detach key %q", params.ErrInvalidParams, key)
+			}
+		default:
+			return nil, fmt.Errorf("%w: invalid detach key %q", params.ErrInvalidParams, key)
+		}
+	}
+	return sequence, nil
+}
+
+// captureBuffer collects output up to a limit and signals once the limit is
+// reached. Writes beyond the limit are discarded, but reported as written,
+// so the docker cli is never blocked on a full pipe.
+type captureBuffer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	limit int
+	full  chan struct{}
+}
+
+func newCaptureBuffer(limit int) *captureBuffer {
+	return &captureBuffer{limit: limit, full: make(chan struct{})}
+}
+
+func (c *captureBuffer) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	remaining := c.limit - c.buf.Len()
+	if remaining <= 0 {
+		return len(p), nil
+	}
+	if len(p) >= remaining {
+		c.buf.Write(p[:remaining])
+		close(c.full)
+		return len(p), nil
+	}
+	c.buf.Write(p)
+	return len(p), nil
+}
+
+// Full returns a channel which is closed once the limit has been reached.
+func (c *captureBuffer) Full() <-chan struct{} {
+	return c.full
+}
+
+func (c *captureBuffer) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.String()
 }
 
 // WithAttachTool is a convenience function to add the AttachTool to the MCP server
 func WithAttachTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(AttachTool, AttachHandler)
+	s.AddTool(AttachTool, params.Validate(AttachTool, AttachHandler))
 	return s
 }
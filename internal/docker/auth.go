@@ -0,0 +1,127 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// In this file, we define the registry credentials sent along with the
+// Engine API pulls, which are read from the cli configuration the way the
+// cli reads them: from the credential helper of the registry, when there
+// is one, or from the auths of the configuration file.
+
+// dockerHubServer is the server address the credentials of Docker Hub are
+// stored under.
+const dockerHubServer = "https://index.docker.io/v1/"
+
+// registryAuth is the payload of the X-Registry-Auth header.
+type registryAuth struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+	ServerAddress string `json:"serveraddress"`
+}
+
+// registryHost returns the registry of the repository, e.g. ghcr.io for
+// ghcr.io/org/app, and docker.io when the repository names none.
+func registryHost(repository string) string {
+	first, _, found := strings.Cut(repository, "/")
+	if !found || (!strings.ContainsAny(first, ".:") && first != "localhost") {
+		return "docker.io"
+	}
+	return normalizeHost(first)
+}
+
+// normalizeHost returns the host of a server address of the
+// configuration, e.g. docker.io for https://index.docker.io/v1/.
+func normalizeHost(address string) string {
+	address = strings.TrimPrefix(strings.TrimPrefix(address, "https://"), "http://")
+	host, _, _ := strings.Cut(address, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return "docker.io"
+	}
+	return host
+}
+
+// registryAuthHeader returns the X-Registry-Auth header of the pulls of
+// the repository, or an empty string when no credentials are configured
+// for its registry.
+func registryAuthHeader(ctx context.Context, repository string) (string, error) {
+	config, err := readDockerConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the docker config: %w", err)
+	}
+	host := registryHost(repository)
+	server := host
+	if host == "docker.io" {
+		server = dockerHubServer
+	}
+	auth, found, err := config.credentials(ctx, host, server)
+	if err != nil || !found {
+		return "", err
+	}
+	authBytes, err := json.Marshal(auth)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(authBytes), nil
+}
+
+// credentials returns the credentials of the registry host, stored under
+// the given server address.
+func (c dockerConfig) credentials(ctx context.Context, host, server string) (registryAuth, bool, error) {
+	helper := c.CredsStore
+	for address, registryHelper := range c.CredHelpers {
+		if normalizeHost(address) == host {
+			helper = registryHelper
+		}
+	}
+	if helper != "" {
+		return helperCredentials(ctx, helper, server)
+	}
+
+	for address, entry := range c.Auths {
+		if normalizeHost(address) != host {
+			continue
+		}
+		auth := registryAuth{IdentityToken: entry.IdentityToken, ServerAddress: server}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return registryAuth{}, false, fmt.Errorf("invalid auth of %s in the docker config: %w", address, err)
+			}
+			auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		return auth, true, nil
+	}
+	return registryAuth{}, false, nil
+}
+
+// helperCredentials returns the credentials of the server held by the
+// credential helper. The helper failing, e.g. as it holds no credentials
+// for the server, is the same as having none.
+func helperCredentials(ctx context.Context, helper, server string) (registryAuth, bool, error) {
+	helperCmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	helperCmd.Stdin = strings.NewReader(server)
+	outBytes, err := helperCmd.Output()
+	if err != nil {
+		return registryAuth{}, false, nil
+	}
+	var creds struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(outBytes, &creds); err != nil {
+		return registryAuth{}, false, fmt.Errorf("failed to parse the credentials of %s: %w", server, err)
+	}
+	// Identity tokens are returned with the <token> user name.
+	if creds.Username == "<token>" {
+		return registryAuth{IdentityToken: creds.Secret, ServerAddress: server}, true, nil
+	}
+	return registryAuth{Username: creds.Username, Password: creds.Secret, ServerAddress: server}, true, nil
+}
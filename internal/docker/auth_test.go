@@ -0,0 +1,143 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeCredentialHelper installs a docker-credential-<name> helper on the
+// PATH, which returns the given user name for every server.
+func fakeCredentialHelper(t *testing.T, binDir, name, username string) {
+	t.Helper()
+	script := "#!/bin/sh\nread server\necho '{\"ServerURL\": \"'$server'\", \"Username\": \"" + username + "\", \"Secret\": \"secret\"}'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "docker-credential-"+name), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryAuthHeader(t *testing.T) {
+	binDir := t.TempDir()
+	fakeCredentialHelper(t, binDir, "store", "store-user")
+	fakeCredentialHelper(t, binDir, "ghcr", "ghcr-user")
+	fakeCredentialHelper(t, binDir, "token", "<token>")
+	// The failing helper holds no credentials.
+	if err := os.WriteFile(filepath.Join(binDir, "docker-credential-failing"), []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	encoded := func(pair string) string { return base64.StdEncoding.EncodeToString([]byte(pair)) }
+	tests := []struct {
+		name       string
+		config     string
+		repository string
+		want       *registryAuth
+	}{
+		{
+			name:       "no config",
+			repository: "alpine",
+		},
+		{
+			name:       "auths of docker hub",
+			config:     `{"auths": {"https://index.docker.io/v1/": {"auth": "` + encoded("hub-user:pass") + `"}}}`,
+			repository: "library/alpine",
+			want:       &registryAuth{Username: "hub-user", Password: "pass", ServerAddress: dockerHubServer},
+		},
+		{
+			name:       "auths of another registry",
+			config:     `{"auths": {"https://index.docker.io/v1/": {"auth": "` + encoded("hub-user:pass") + `"}}}`,
+			repository: "ghcr.io/org/app",
+		},
+		{
+			name:       "auths identity token",
+			config:     `{"auths": {"registry.local:5000": {"identitytoken": "token"}}}`,
+			repository: "registry.local:5000/app",
+			want:       &registryAuth{IdentityToken: "token", ServerAddress: "registry.local:5000"},
+		},
+		{
+			name:       "creds store over auths",
+			config:     `{"credsStore": "store", "auths": {"ghcr.io": {"auth": "` + encoded("auths-user:pass") + `"}}}`,
+			repository: "ghcr.io/org/app",
+			want:       &registryAuth{Username: "store-user", Password: "secret", ServerAddress: "ghcr.io"},
+		},
+		{
+			name:       "cred helper over creds store",
+			config:     `{"credsStore": "store", "credHelpers": {"ghcr.io": "ghcr"}}`,
+			repository: "ghcr.io/org/app",
+			want:       &registryAuth{Username: "ghcr-user", Password: "secret", ServerAddress: "ghcr.io"},
+		},
+		{
+			name:       "cred helper of another registry",
+			config:     `{"credsStore": "store", "credHelpers": {"ghcr.io": "ghcr"}}`,
+			repository: "alpine",
+			want:       &registryAuth{Username: "store-user", Password: "secret", ServerAddress: dockerHubServer},
+		},
+		{
+			name:       "cred helper identity token",
+			config:     `{"credHelpers": {"ghcr.io": "token"}}`,
+			repository: "ghcr.io/org/app",
+			want:       &registryAuth{IdentityToken: "secret", ServerAddress: "ghcr.io"},
+		},
+		{
+			name:       "failing helper",
+			config:     `{"credsStore": "failing", "auths": {"ghcr.io": {"auth": "` + encoded("auths-user:pass") + `"}}}`,
+			repository: "ghcr.io/org/app",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configDir := t.TempDir()
+			if tt.config != "" {
+				if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(tt.config), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("DOCKER_CONFIG", configDir)
+
+			header, err := registryAuthHeader(context.Background(), tt.repository)
+			if err != nil {
+				t.Fatalf("registryAuthHeader failed: %v", err)
+			}
+			if tt.want == nil {
+				if header != "" {
+					t.Errorf("registryAuthHeader = %q, want no credentials", header)
+				}
+				return
+			}
+			authBytes, err := base64.URLEncoding.DecodeString(header)
+			if err != nil {
+				t.Fatalf("invalid header %q: %v", header, err)
+			}
+			var got registryAuth
+			if err := json.Unmarshal(authBytes, &got); err != nil {
+				t.Fatalf("invalid header %q: %v", header, err)
+			}
+			if got != *tt.want {
+				t.Errorf("registryAuthHeader = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryHost(t *testing.T) {
+	tests := []struct {
+		repository, want string
+	}{
+		{"alpine", "docker.io"},
+		{"library/alpine", "docker.io"},
+		{"docker.io/library/alpine", "docker.io"},
+		{"index.docker.io/library/alpine", "docker.io"},
+		{"ghcr.io/org/app", "ghcr.io"},
+		{"localhost/app", "localhost"},
+		{"registry.local:5000/app", "registry.local:5000"},
+	}
+	for _, tt := range tests {
+		if got := registryHost(tt.repository); got != tt.want {
+			t.Errorf("registryHost(%q) = %q, want %q", tt.repository, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// runDocker runs the docker cli with the given arguments and returns its
+// standard output. On failure, the returned error carries the cli's stderr.
+func runDocker(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	dockerCmd := exec.CommandContext(ctx, "docker", args...)
+	dockerCmd.Stderr = &stderr
+	outBytes, err := dockerCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w \n %s", err, stderr.String())
+	}
+	return outBytes, nil
+}
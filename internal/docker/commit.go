@@ -1,26 +1,34 @@
 package docker
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"os/exec"
+	"regexp"
+	"strings"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// tagRegexp is the grammar of image tags.
+var tagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
 var CommitTool = mcp.NewTool("docker_commit",
 	mcp.WithDescription("Creates a new image from a container's changes"),
 	mcp.WithString("containerID",
 		mcp.Required(),
 		mcp.Description("The ID of the container to commit"),
+		params.Format(params.ContainerFormat),
 	),
 	mcp.WithString("repository",
 		mcp.Description("The repository name for the new image"),
+		params.Format(params.ImageFormat),
 	),
 	mcp.WithString("tag",
-		mcp.Description("The tag for the new image"),
+		mcp.Description("The tag for the new image, requires repository"),
 	),
 	mcp.WithString("message",
 		mcp.Description("A commit message"),
@@ -28,65 +36,192 @@ var CommitTool = mcp.NewTool("docker_commit",
 	mcp.WithString("author",
 		mcp.Description("The author of the new image"),
 	),
-	mcp.WithString("change",
-		mcp.Description("Apply a Dockerfile instruction to the container's filesystem"),
+	mcp.WithArray("change",
+		mcp.Description("Dockerfile instructions to apply to the created image"),
+		params.StringArray(),
 	),
-	mcp.WithString("pause",
+	mcp.WithBoolean("pause",
 		mcp.Description("Pause the container during commit"),
+		mcp.DefaultBool(true),
 	),
+	output.StoreOption(),
 )
 
+// CommitResult is the structured result of the commit tool.
+type CommitResult struct {
+	ImageID     string       `json:"imageID"`
+	Reference   string       `json:"reference,omitempty"`
+	BaseImageID string       `json:"baseImageID"`
+	AddedLayers []string     `json:"addedLayers"`
+	Changes     []FileChange `json:"changes"`
+}
+
+// FileChange is a single entry of the container's filesystem diff.
+type FileChange struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
 // CommitHandler is the handler function that handles commit requests
 // and actually makes a use of docker cli tool to commit the container.
 func CommitHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	containerID := req.Params.Arguments["containerID"].(string)
+	containerID, err := params.String(req, "containerID")
+	if err != nil {
+		return nil, err
+	}
+	repository, err := params.OptionalString(req, "repository", "")
+	if err != nil {
+		return nil, err
+	}
+	tag, err := params.OptionalString(req, "tag", "")
+	if err != nil {
+		return nil, err
+	}
+	message, err := params.OptionalString(req, "message", "")
+	if err != nil {
+		return nil, err
+	}
+	author, err := params.OptionalString(req, "author", "")
+	if err != nil {
+		return nil, err
+	}
+	changes, err := params.StringSlice(req, "change")
+	if err != nil {
+		return nil, err
+	}
+	pause, err := params.Bool(req, "pause", true)
+	if err != nil {
+		return nil, err
+	}
 
-	args := []string{"commit", containerID}
+	reference, err := commitReference(repository, tag)
+	if err != nil {
+		return nil, err
+	}
 
-	repository, exist := req.Params.Arguments["repository"]
-	if exist {
-		args = append(args, repository.(string))
+	baseBytes, err := runDocker(ctx, "inspect", "--type", "container", "--format", "{{.Image}}", containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s: %w", containerID, err)
 	}
+	baseImageID := strings.TrimSpace(string(baseBytes))
 
-	tag, exist := req.Params.Arguments["tag"]
-	if exist {
-		args = append(args, tag.(string))
+	fileChanges, err := containerChanges(ctx, containerID)
+	if err != nil {
+		return nil, err
 	}
 
-	message, exist := req.Params.Arguments["message"]
-	if exist {
-		args = append(args, "--message", message.(string))
+	args := []string{"commit", fmt.Sprintf("--pause=%t", pause)}
+	if message != "" {
+		args = append(args, "--message", message)
+	}
+	if author != "" {
+		args = append(args, "--author", author)
+	}
+	for _, change := range changes {
+		args = append(args, "--change", change)
 	}
+	args = append(args, containerID)
+	if reference != "" {
+		args = append(args, reference)
+	}
+
+	outBytes, err := runDocker(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit container: %w", err)
+	}
+	imageID := strings.TrimSpace(string(outBytes))
+
+	baseLayers, err := imageLayers(ctx, baseImageID)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := imageLayers(ctx, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := CommitResult{
+		ImageID:     imageID,
+		Reference:   reference,
+		BaseImageID: baseImageID,
+		AddedLayers: addedLayers(baseLayers, layers),
+		Changes:     fileChanges,
+	}
+	resultBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode commit result: %w", err)
+	}
+
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
 
-	author, exist := req.Params.Arguments["author"]
-	if exist {
-		args = append(args, "--author", author.(string))
+// commitReference builds the repository[:tag] reference of the new image.
+func commitReference(repository, tag string) (string, error) {
+	if repository == "" {
+		if tag != "" {
+			return "", fmt.Errorf("%w: argument %q requires %q", params.ErrInvalidParams, "tag", "repository")
+		}
+		return "", nil
+	}
+	if tag == "" {
+		return repository, nil
+	}
+	// A colon after the last slash starts a tag, the ones before are ports.
+	if strings.Contains(repository, "@") || strings.LastIndex(repository, ":") > strings.LastIndex(repository, "/") {
+		return "", fmt.Errorf("%w: argument %q already has a tag or digest, which conflicts with %q", params.ErrInvalidParams, "repository", "tag")
+	}
+	if !tagRegexp.MatchString(tag) {
+		return "", fmt.Errorf("%w: argument %q is not a valid tag: %q", params.ErrInvalidParams, "tag", tag)
 	}
+	return repository + ":" + tag, nil
+}
 
-	change, exist := req.Params.Arguments["change"]
-	if exist {
-		args = append(args, "--change", change.(string))
+// containerChanges returns the filesystem changes of the container
+// compared to its image, as reported by docker diff.
+func containerChanges(ctx context.Context, containerID string) ([]FileChange, error) {
+	outBytes, err := runDocker(ctx, "diff", containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to show changes for container %s: %w", containerID, err)
 	}
 
-	pause, exist := req.Params.Arguments["pause"]
-	if exist {
-		args = append(args, "--pause", pause.(string))
+	changes := []FileChange{}
+	for _, line := range strings.Split(string(outBytes), "\n") {
+		kind, path, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found {
+			continue
+		}
+		changes = append(changes, FileChange{Kind: kind, Path: path})
 	}
+	return changes, nil
+}
 
-	var stderr bytes.Buffer
-	commitCmd := exec.Command("docker", args...)
-	commitCmd.Stderr = &stderr
-	outBytes, err := commitCmd.Output()
+// imageLayers returns the diff IDs of the layers of the given image.
+func imageLayers(ctx context.Context, image string) ([]string, error) {
+	outBytes, err := runDocker(ctx, "image", "inspect", "--format", "{{json .RootFS.Layers}}", image)
 	if err != nil {
-		return nil, fmt.Errorf("failed to commit container: %w\n %s", err, stderr.String())
+		return nil, fmt.Errorf("failed to inspect image %s: %w", image, err)
 	}
-	result := string(outBytes)
+	var layers []string
+	if err := json.Unmarshal(outBytes, &layers); err != nil {
+		return nil, fmt.Errorf("failed to parse layers of image %s: %w", image, err)
+	}
+	return layers, nil
+}
 
-	return mcp.NewToolResultText(fmt.Sprintf("%s", result)), nil
+// addedLayers returns the layers of image stacked on top of base. A
+// committed image extends the layers of its base, so they're compared by
+// position rather than by membership: the diff ID of a new layer may well
+// appear in the base too, e.g. the one of an empty layer.
+func addedLayers(base, image []string) []string {
+	common := 0
+	for common < len(base) && common < len(image) && base[common] == image[common] {
+		common++
+	}
+	return append([]string{}, image[common:]...)
 }
 
 // WithCommitTool adds the commit tool to the MCP server
 func WithCommitTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(CommitTool, CommitHandler)
+	s.AddTool(CommitTool, params.Validate(CommitTool, CommitHandler))
 	return s
 }
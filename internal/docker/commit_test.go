@@ -0,0 +1,45 @@
+package docker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+)
+
+func TestCommitReference(t *testing.T) {
+	tests := []struct {
+		repository, tag string
+		want            string
+		valid           bool
+	}{
+		{"", "", "", true},
+		{"app", "", "app", true},
+		{"app:1.0", "", "app:1.0", true},
+		{"app", "1.0", "app:1.0", true},
+		{"localhost:5000/team/app", "v1_rc.2-x", "localhost:5000/team/app:v1_rc.2-x", true},
+		{"app", strings.Repeat("a", 128), "app:" + strings.Repeat("a", 128), true},
+		{"", "1.0", "", false},
+		{"app:1.0", "2.0", "", false},
+		{"localhost:5000/app:1.0", "2.0", "", false},
+		{"app@sha256:0123456789abcdef0123456789abcdef", "2.0", "", false},
+		{"app", "-1.0", "", false},
+		{"app", ".hidden", "", false},
+		{"app", "1.0:latest", "", false},
+		{"app", "1.0 --privileged", "", false},
+		{"app", strings.Repeat("a", 129), "", false},
+	}
+	for _, tt := range tests {
+		got, err := commitReference(tt.repository, tt.tag)
+		if !tt.valid {
+			if !errors.Is(err, params.ErrInvalidParams) {
+				t.Errorf("commitReference(%q, %q) = %q, %v, want an invalid params error", tt.repository, tt.tag, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("commitReference(%q, %q) = %q, %v, want %q", tt.repository, tt.tag, got, err, tt.want)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os/exec"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -16,13 +18,18 @@ var DiffTool = mcp.NewTool("docker_diff",
 	mcp.WithString("containerID",
 		mcp.Required(),
 		mcp.Description("The ID of the container to show changes for"),
+		params.Format(params.ContainerFormat),
 	),
+	output.StoreOption(),
 )
 
 // DiffHandler is the handler function that handles diff requests
 // and actually makes a use of docker cli tool to show the changes made
 func DiffHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	containerID := req.Params.Arguments["containerID"].(string)
+	containerID, err := params.String(req, "containerID")
+	if err != nil {
+		return nil, err
+	}
 
 	var stderr bytes.Buffer
 	diffCmd := exec.Command("docker", "diff", containerID)
@@ -38,6 +45,6 @@ func DiffHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolRes
 
 // WithDiffTool adds the DiffTool to the MCP server
 func WithDiffTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(DiffTool, DiffHandler)
+	s.AddTool(DiffTool, params.Validate(DiffTool, DiffHandler))
 	return s
 }
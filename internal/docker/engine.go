@@ -0,0 +1,107 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// In this file, we define the client of the docker Engine API, used where
+// the cli doesn't expose what a tool needs, e.g. the progress of a pull.
+// Only plain unix socket and TCP daemons are supported, the others, e.g.
+// TLS daemons or docker contexts, are left to the cli.
+
+// defaultDockerHost is the daemon the cli talks to without configuration.
+const defaultDockerHost = "unix:///var/run/docker.sock"
+
+// errEngineUnsupported is returned when the daemon the cli talks to can't
+// be reached through the Engine API client.
+var errEngineUnsupported = errors.New("docker daemon not reachable through the Engine API")
+
+// engineClient returns an HTTP client of the Engine API, along with the
+// base URL of its requests.
+func engineClient() (*http.Client, string, error) {
+	if os.Getenv("DOCKER_CONTEXT") != "" || os.Getenv("DOCKER_TLS_VERIFY") != "" || currentDockerContext() != "" {
+		return nil, "", errEngineUnsupported
+	}
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = defaultDockerHost
+	}
+	hostURL, err := url.Parse(host)
+	if err != nil {
+		return nil, "", errEngineUnsupported
+	}
+
+	switch hostURL.Scheme {
+	case "unix":
+		socket := hostURL.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &http.Client{Transport: transport}, "http://docker", nil
+	case "tcp":
+		return &http.Client{}, "http://" + hostURL.Host, nil
+	default:
+		return nil, "", errEngineUnsupported
+	}
+}
+
+// dockerConfig is the part of the cli configuration file the tools need.
+type dockerConfig struct {
+	CurrentContext string `json:"currentContext"`
+	// Auths holds the credentials of the registries, by server address,
+	// as base64 encoded user:password pairs or identity tokens.
+	Auths map[string]struct {
+		Auth          string `json:"auth"`
+		IdentityToken string `json:"identitytoken"`
+	} `json:"auths"`
+	// CredsStore and CredHelpers name the credential helpers which hold
+	// the credentials instead, for every registry or for some of them.
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// readDockerConfig reads the configuration file of the cli, from
+// $DOCKER_CONFIG or ~/.docker. A missing file is an empty configuration.
+func readDockerConfig() (dockerConfig, error) {
+	var config dockerConfig
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return config, nil
+		}
+		configDir = filepath.Join(home, ".docker")
+	}
+	configBytes, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// currentDockerContext returns the docker context selected with docker
+// context use, or an empty string for the default one.
+func currentDockerContext() string {
+	config, err := readDockerConfig()
+	if err != nil || config.CurrentContext == "default" {
+		return ""
+	}
+	return strings.TrimSpace(config.CurrentContext)
+}
@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -16,33 +18,50 @@ var ExecTool = mcp.NewTool("docker_exec",
 	mcp.WithString("containerID",
 		mcp.Required(),
 		mcp.Description("The ID of the container to execute the command in"),
+		params.Format(params.ContainerFormat),
 	),
 	mcp.WithString("command",
 		mcp.Required(),
 		mcp.Description("The command to execute in the container"),
 	),
-	mcp.WithString("interactive",
+	mcp.WithBoolean("interactive",
 		mcp.Description("Run the command in interactive mode"),
+		mcp.DefaultBool(false),
 	),
-	mcp.WithString("detach",
+	mcp.WithBoolean("detach",
 		mcp.Description("Run the command in detached mode"),
+		mcp.DefaultBool(false),
 	),
+	output.StoreOption(),
 )
 
 // ExecHandler is the handler function that handles exec requests
 func ExecHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	containerID := req.Params.Arguments["containerID"].(string)
-	command := req.Params.Arguments["command"].(string)
-	interactive, _ := req.Params.Arguments["interactive"].(string)
-	detach, _ := req.Params.Arguments["detach"].(string)
+	containerID, err := params.String(req, "containerID")
+	if err != nil {
+		return nil, err
+	}
+	command, err := params.String(req, "command")
+	if err != nil {
+		return nil, err
+	}
+	interactive, err := params.Bool(req, "interactive", false)
+	if err != nil {
+		return nil, err
+	}
+	detach, err := params.Bool(req, "detach", false)
+	if err != nil {
+		return nil, err
+	}
 
-	args := []string{"exec", containerID}
-	if interactive != "" {
+	args := []string{"exec"}
+	if interactive {
 		args = append(args, "-i")
 	}
-	if detach != "" {
+	if detach {
 		args = append(args, "-d")
 	}
+	args = append(args, containerID)
 	// Split the command string into individual arguments
 	cmdArgs := strings.Fields(command)
 	args = append(args, cmdArgs...)
@@ -61,6 +80,6 @@ func ExecHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolRes
 }
 
 func WithExecTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(ExecTool, ExecHandler)
+	s.AddTool(ExecTool, params.Validate(ExecTool, ExecHandler))
 	return s
 }
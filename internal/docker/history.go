@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os/exec"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -16,37 +18,50 @@ var HistoryTool = mcp.NewTool("docker_history",
 	mcp.WithString("image",
 		mcp.Required(),
 		mcp.Description("The name of the image to show history for"),
+		params.Format(params.ImageFormat),
 	),
 	mcp.WithString("format",
 		mcp.Description("Format the output using a custom template"),
 	),
-	mcp.WithString("no-trunc",
+	mcp.WithBoolean("no-trunc",
 		mcp.Description("Don't truncate output"),
+		mcp.DefaultBool(false),
 	),
-	mcp.WithString("human",
+	mcp.WithBoolean("human",
 		mcp.Description("Format the output in human-readable format"),
+		mcp.DefaultBool(true),
 	),
+	output.StoreOption(),
 )
 
 // HistoryHandler is the handler function that handles history requests
 func HistoryHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	image := req.Params.Arguments["image"].(string)
+	image, err := params.String(req, "image")
+	if err != nil {
+		return nil, err
+	}
+	format, err := params.OptionalString(req, "format", "")
+	if err != nil {
+		return nil, err
+	}
+	noTrunc, err := params.Bool(req, "no-trunc", false)
+	if err != nil {
+		return nil, err
+	}
+	human, err := params.Bool(req, "human", true)
+	if err != nil {
+		return nil, err
+	}
 
 	args := []string{"history", image}
-
-	format, exist := req.Params.Arguments["format"]
-	if exist {
-		args = append(args, "--format", format.(string))
+	if format != "" {
+		args = append(args, "--format", format)
 	}
-
-	_, exist = req.Params.Arguments["no-trunc"]
-	if exist {
+	if noTrunc {
 		args = append(args, "--no-trunc")
 	}
-
-	_, exist = req.Params.Arguments["human"]
-	if exist {
-		args = append(args, "--human")
+	if !human {
+		args = append(args, "--human=false")
 	}
 
 	var stderr bytes.Buffer
@@ -62,6 +77,6 @@ func HistoryHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTool
 }
 
 func WithHistoryTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(HistoryTool, HistoryHandler)
+	s.AddTool(HistoryTool, params.Validate(HistoryTool, HistoryHandler))
 	return s
 }
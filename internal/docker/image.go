@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os/exec"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -15,6 +17,9 @@ var ImageListTools = mcp.NewTool("docker_image",
 	mcp.WithString("list",
 		mcp.Description("List all images"),
 	),
+	output.CursorOption(),
+	output.PageSizeOption(),
+	output.StoreOption(),
 )
 
 var ImageInspectTool = mcp.NewTool("docker_image_inspect",
@@ -22,13 +27,16 @@ var ImageInspectTool = mcp.NewTool("docker_image_inspect",
 	mcp.WithString("imageID",
 		mcp.Required(),
 		mcp.Description("The ID of the image to inspect"),
+		params.Format(params.ImageFormat),
 	),
 	mcp.WithString("format",
 		mcp.Description("Format the output using a Go template"),
 	),
-	mcp.WithString("size",
+	mcp.WithBoolean("size",
 		mcp.Description("Display image size"),
+		mcp.DefaultBool(false),
 	),
+	output.StoreOption(),
 )
 
 var ImageHistoryTool = mcp.NewTool("docker_image_history",
@@ -36,18 +44,25 @@ var ImageHistoryTool = mcp.NewTool("docker_image_history",
 	mcp.WithString("imageID",
 		mcp.Required(),
 		mcp.Description("The ID of the image to show history for"),
+		params.Format(params.ImageFormat),
 	),
 	mcp.WithString("format",
 		mcp.Description("Format the output using a Go template"),
 	),
-	mcp.WithString("no-trunc",
+	mcp.WithBoolean("no-trunc",
 		mcp.Description("Don't truncate output"),
+		mcp.DefaultBool(false),
 	),
+	output.StoreOption(),
 )
 
 // ImageListHandler is the handler function that handles image requests
 func ImageListHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Implement the logic to list Docker images
+	page, err := output.ParsePage(req)
+	if err != nil {
+		return nil, err
+	}
 	var stderr bytes.Buffer
 	diffCmd := exec.Command("docker", "image", "ls")
 	diffCmd.Stderr = &stderr
@@ -55,25 +70,34 @@ func ImageListHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTo
 	if err != nil {
 		return nil, fmt.Errorf("failed to list images: %w \n %s", err, stderr.String())
 	}
-	result := string(outBytes)
+	result, next := output.PaginateLines(string(outBytes), page, true)
 
-	return mcp.NewToolResultText(fmt.Sprintf("%s", result)), nil
+	return output.WithNextCursor(mcp.NewToolResultText(result), next), nil
 }
 
 // ImageInspectHandler is the handler function that handles image inspect requests
 func ImageInspectHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Implement the logic to inspect a Docker image
 	// This is a placeholder implementation
-	imageID := req.Params.Arguments["imageID"].(string)
+	imageID, err := params.String(req, "imageID")
+	if err != nil {
+		return nil, err
+	}
+	size, err := params.Bool(req, "size", false)
+	if err != nil {
+		return nil, err
+	}
+	format, err := params.OptionalString(req, "format", "")
+	if err != nil {
+		return nil, err
+	}
+
 	args := []string{"image", "inspect", imageID}
-	_, exist := req.Params.Arguments["size"]
-	if exist {
+	if size {
 		args = append(args, "--size")
 	}
-
-	format, exist := req.Params.Arguments["format"]
-	if exist {
-		args = append(args, "--format", format.(string))
+	if format != "" {
+		args = append(args, "--format", format)
 	}
 	var stderr bytes.Buffer
 	diffCmd := exec.Command("docker", args...)
@@ -93,14 +117,24 @@ func ImageInspectHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Cal
 func ImageHistoryHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Implement the logic to show the history of a Docker image
 	// This is a placeholder implementation
-	imageID := req.Params.Arguments["imageID"].(string)
+	imageID, err := params.String(req, "imageID")
+	if err != nil {
+		return nil, err
+	}
+	format, err := params.OptionalString(req, "format", "")
+	if err != nil {
+		return nil, err
+	}
+	noTrunc, err := params.Bool(req, "no-trunc", false)
+	if err != nil {
+		return nil, err
+	}
+
 	args := []string{"image", "history", imageID}
-	format, exist := req.Params.Arguments["format"]
-	if exist {
-		args = append(args, "--format", format.(string))
+	if format != "" {
+		args = append(args, "--format", format)
 	}
-	_, exist = req.Params.Arguments["no-trunc"]
-	if exist {
+	if noTrunc {
 		args = append(args, "--no-trunc")
 	}
 
@@ -119,8 +153,8 @@ func ImageHistoryHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Cal
 }
 
 func WithImageTools(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(ImageListTools, ImageListHandler)
-	s.AddTool(ImageInspectTool, ImageInspectHandler)
-	s.AddTool(ImageHistoryTool, ImageHistoryHandler)
+	s.AddTool(ImageListTools, params.Validate(ImageListTools, ImageListHandler))
+	s.AddTool(ImageInspectTool, params.Validate(ImageInspectTool, ImageInspectHandler))
+	s.AddTool(ImageHistoryTool, params.Validate(ImageHistoryTool, ImageHistoryHandler))
 	return s
 }
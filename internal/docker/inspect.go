@@ -1,44 +1,170 @@
 package docker
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"os/exec"
+	"strconv"
+	"strings"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // In this file, we define the inspect tool for Docker,
-// which allows users to inspect a container, image, or volume,
-// while using docker cli tool.
+// which allows users to inspect a container, image, volume or any
+// other docker object, while using docker cli tool.
 var InspectTool = mcp.NewTool("docker_inspect",
-	mcp.WithDescription("Inspects a docker container, image, or volume"),
-	mcp.WithString("containerID",
+	mcp.WithDescription("Inspects a docker container, image, volume, network, plugin, node or service"),
+	mcp.WithString("target",
 		mcp.Required(),
-		mcp.Description("The ID of the container to inspect"),
+		mcp.Description("The ID or name of the object to inspect"),
 	),
+	mcp.WithString("type",
+		mcp.Description("The type of the object to inspect, docker picks the first match when omitted"),
+		mcp.Enum("container", "image", "volume", "network", "plugin", "node", "service"),
+	),
+	mcp.WithString("format",
+		mcp.Description("Format the output using a Go template, e.g. {{json .State}}"),
+	),
+	mcp.WithString("field",
+		mcp.Description("Select a single field of the inspect JSON, e.g. .State.Health or .Mounts[0].Source"),
+	),
+	output.StoreOption(),
 )
 
 // InspectHandler is the handler function that handles inspection requests
-// and actually makes a use of docker cli tool to inspect the container.
+// and actually makes a use of docker cli tool to inspect the object.
 func InspectHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	containerID := req.Params.Arguments["containerID"].(string)
+	target, err := params.String(req, "target")
+	if err != nil {
+		return nil, err
+	}
+	objectType, err := params.OptionalString(req, "type", "")
+	if err != nil {
+		return nil, err
+	}
+	format, err := params.OptionalString(req, "format", "")
+	if err != nil {
+		return nil, err
+	}
+	field, err := params.OptionalString(req, "field", "")
+	if err != nil {
+		return nil, err
+	}
+	if format != "" && field != "" {
+		return nil, fmt.Errorf("%w: arguments %q and %q are mutually exclusive", params.ErrInvalidParams, "format", "field")
+	}
+
+	args := []string{"inspect"}
+	if objectType != "" {
+		args = append(args, "--type", objectType)
+	}
+	if format != "" {
+		args = append(args, "--format", format)
+	}
+	args = append(args, target)
+
+	outBytes, err := runDocker(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", target, err)
+	}
+	if format != "" {
+		return mcp.NewToolResultText(string(outBytes)), nil
+	}
+
+	var objects []interface{}
+	if err := json.Unmarshal(outBytes, &objects); err != nil {
+		return nil, fmt.Errorf("failed to parse inspect output of %s: %w", target, err)
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no object found with ID %s", target)
+	}
+
+	var result interface{} = objects[0]
+	if field != "" {
+		result, err = selectField(objects[0], field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select %s of %s: %w", field, target, err)
+		}
+	}
 
-	var stderr bytes.Buffer
-	inspectCmd := exec.Command("docker", "inspect", containerID)
-	inspectCmd.Stderr = &stderr
-	outBytes, err := inspectCmd.Output()
+	resultBytes, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
-		return nil, fmt.Errorf("failed to inspect container: %w \n %s", err, stderr.String())
+		return nil, fmt.Errorf("failed to encode inspect result: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// selectField walks the decoded JSON value along a selector such as
+// .State.Health, .Mounts[0].Source or .Config.Labels["com.example.key"].
+func selectField(value interface{}, selector string) (interface{}, error) {
+	steps, err := parseSelector(selector)
+	if err != nil {
+		return nil, err
 	}
-	result := string(outBytes)
 
-	return mcp.NewToolResultText(fmt.Sprintf("%s", result)), nil
+	current := value
+	for _, step := range steps {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, exist := node[step]
+			if !exist {
+				return nil, fmt.Errorf("field %q not found", step)
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(step)
+			if err != nil {
+				return nil, fmt.Errorf("cannot index array with %q", step)
+			}
+			if index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("index %d out of range, array has %d elements", index, len(node))
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("cannot select %q of a scalar value", step)
+		}
+	}
+	return current, nil
+}
+
+// parseSelector splits a selector into its steps, where each step is either
+// a key of an object or an index of an array.
+func parseSelector(selector string) ([]string, error) {
+	var steps []string
+	rest := strings.TrimPrefix(strings.TrimSpace(selector), ".")
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated [ in selector %q", params.ErrInvalidParams, selector)
+			}
+			step := rest[1:end]
+			if unquoted, err := strconv.Unquote(step); err == nil {
+				step = unquoted
+			}
+			steps = append(steps, step)
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("%w: empty field in selector %q", params.ErrInvalidParams, selector)
+			}
+			steps = append(steps, rest[:end])
+			rest = strings.TrimPrefix(rest[end:], ".")
+		}
+	}
+	return steps, nil
 }
 
 func WithInspectTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(InspectTool, InspectHandler)
+	s.AddTool(InspectTool, params.Validate(InspectTool, InspectHandler))
 	return s
 }
@@ -0,0 +1,151 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/sbom"
+	"github.com/mark3labs/mcp-docker/internal/trivy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the docker_license_report tool, which lists the
+// licenses shipped in an image by category, from its SBOM or from the
+// trivy license scanner, and flags the ones violating a denylist.
+
+var LicenseReportTool = mcp.NewTool("docker_license_report",
+	mcp.WithDescription("Report the licenses of the packages of a Docker image by category, permissive, copyleft or unknown, flagging denied licenses"),
+	mcp.WithString("image",
+		mcp.Description("The name of the image to report the licenses of, unless file is given"),
+		params.Format(params.ImageFormat),
+	),
+	mcp.WithString("file",
+		mcp.Description("The path of an SPDX or CycloneDX JSON document, relative to the workspace, to report the licenses of"),
+	),
+	mcp.WithString("source",
+		mcp.Description("Where the licenses come from, the SBOM of the image or the trivy license scanner"),
+		mcp.Enum("sbom", "trivy"),
+		mcp.DefaultString("sbom"),
+	),
+	mcp.WithArray("denylist",
+		mcp.Description("Licenses which must not ship, as ID prefixes, e.g. AGPL or GPL-3.0, or categories, e.g. copyleft, or forbidden with the trivy source"),
+		params.StringArray(),
+	),
+	mcp.WithString("generator",
+		mcp.Description("The tool generating the SBOM, auto picks the first available of docker sbom, syft and trivy"),
+		mcp.Enum("auto", DockerGenerator, SyftGenerator, TrivyGenerator),
+		mcp.DefaultString("auto"),
+	),
+	output.StoreOption(),
+)
+
+// trivyLicenseCategories maps the categories of the trivy license scanner,
+// which follow the Google license classification, to the report ones.
+var trivyLicenseCategories = map[string]string{
+	"forbidden":    sbom.CategoryCopyleft,
+	"restricted":   sbom.CategoryCopyleft,
+	"reciprocal":   sbom.CategoryCopyleft,
+	"notice":       sbom.CategoryPermissive,
+	"permissive":   sbom.CategoryPermissive,
+	"unencumbered": sbom.CategoryPermissive,
+	"unknown":      sbom.CategoryUnknown,
+}
+
+// trivyLicensedPackages scans the licenses of the image with trivy.
+func trivyLicensedPackages(ctx context.Context, image string) ([]sbom.LicensedPackage, error) {
+	report, _, err := trivy.ScanReport(ctx, "image", image, trivy.ScanOptions{Scanners: []string{"license"}})
+	if err != nil {
+		return nil, err
+	}
+
+	packages := []sbom.LicensedPackage{}
+	for _, result := range report.Results {
+		for _, license := range result.Licenses {
+			name := license.PkgName
+			// Licenses found in files rather than in package metadata.
+			if name == "" {
+				name = license.FilePath
+			}
+			packages = append(packages, sbom.LicensedPackage{
+				Name:           name,
+				License:        license.Name,
+				Category:       trivyLicenseCategories[license.Category],
+				SourceCategory: license.Category,
+			})
+		}
+	}
+	return packages, nil
+}
+
+// LicenseReportResult is the result of the license report tool.
+type LicenseReportResult struct {
+	Artifact string `json:"artifact"`
+	Source   string `json:"source"`
+	sbom.LicenseReport
+}
+
+// LicenseReportHandler is the handler function that handles license report
+// requests, classifying the licenses of the image packages.
+func LicenseReportHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	image, err := params.OptionalString(req, "image", "")
+	if err != nil {
+		return nil, err
+	}
+	file, err := params.OptionalString(req, "file", "")
+	if err != nil {
+		return nil, err
+	}
+	source, err := params.OptionalString(req, "source", "sbom")
+	if err != nil {
+		return nil, err
+	}
+	denylist, err := params.StringSlice(req, "denylist")
+	if err != nil {
+		return nil, err
+	}
+	deny, err := sbom.ParseDenylist(denylist)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", params.ErrInvalidParams, err)
+	}
+	generator, err := params.OptionalString(req, "generator", "auto")
+	if err != nil {
+		return nil, err
+	}
+
+	result := LicenseReportResult{Artifact: image, Source: source}
+	var packages []sbom.LicensedPackage
+	if source == "trivy" {
+		if image == "" || file != "" {
+			return nil, fmt.Errorf("%w: the trivy source requires argument \"image\" and doesn't support \"file\"", params.ErrInvalidParams)
+		}
+		if packages, err = trivyLicensedPackages(ctx, image); err != nil {
+			return nil, err
+		}
+	} else {
+		doc, err := loadSBOM(ctx, image, file, generator)
+		if err != nil {
+			return nil, err
+		}
+		packages = doc.LicensedPackages()
+		if file != "" {
+			result.Artifact = file
+		}
+	}
+
+	result.LicenseReport = sbom.NewLicenseReport(packages, deny)
+	resultBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode license report: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// WithLicenseReportTool adds the LicenseReportTool to the MCP server
+func WithLicenseReportTool(s *server.MCPServer) *server.MCPServer {
+	s.AddTool(LicenseReportTool, params.Validate(LicenseReportTool, LicenseReportHandler))
+	return s
+}
@@ -0,0 +1,209 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/sbom"
+	"github.com/mark3labs/mcp-docker/internal/trivy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the prompts of common docker workflows. Each
+// prompt gathers the output of the relevant tools up front, so the model
+// starts from the facts instead of calling the tools one by one. Outputs
+// which can't be gathered, e.g. when trivy isn't installed, are reported
+// as unavailable rather than failing the prompt.
+
+var DebugContainerPrompt = mcp.NewPrompt("debug_crashing_container",
+	mcp.WithPromptDescription("Debug a crashing container from its inspect output, logs and filesystem changes"),
+	mcp.WithArgument("container",
+		mcp.ArgumentDescription("The ID or name of the container"),
+		mcp.RequiredArgument(),
+	),
+)
+
+var HardenImagePrompt = mcp.NewPrompt("harden_image",
+	mcp.WithPromptDescription("Suggest how to harden an image from its history, vulnerabilities and packages"),
+	mcp.WithArgument("image",
+		mcp.ArgumentDescription("The name of the image"),
+		mcp.RequiredArgument(),
+	),
+)
+
+var ExplainImageSizePrompt = mcp.NewPrompt("explain_image_size",
+	mcp.WithPromptDescription("Explain what makes up the size of an image from the sizes of its layers"),
+	mcp.WithArgument("image",
+		mcp.ArgumentDescription("The name of the image"),
+		mcp.RequiredArgument(),
+	),
+)
+
+// promptArgument returns the argument of the prompt, checked against the
+// format.
+func promptArgument(req mcp.GetPromptRequest, name, format string) (string, error) {
+	value := req.Params.Arguments[name]
+	if err := params.CheckFormat(name, format, value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+func userMessage(content mcp.Content) mcp.PromptMessage {
+	return mcp.NewPromptMessage(mcp.RoleUser, content)
+}
+
+// outputMessage returns the output of a tool as a prompt message, or a
+// note that it's unavailable.
+func outputMessage(title string, output []byte, err error) mcp.PromptMessage {
+	if err != nil {
+		return userMessage(mcp.NewTextContent(fmt.Sprintf("%s: unavailable, %v", title, err)))
+	}
+	return userMessage(mcp.NewTextContent(fmt.Sprintf("%s:\n%s", title, output)))
+}
+
+// resourceMessages returns the contents of a resource as prompt messages.
+func resourceMessages(contents []mcp.ResourceContents) []mcp.PromptMessage {
+	messages := make([]mcp.PromptMessage, 0, len(contents))
+	for _, content := range contents {
+		messages = append(messages, userMessage(mcp.NewEmbeddedResource(content)))
+	}
+	return messages
+}
+
+// templateRequest builds the request reading a resource template, as the
+// MCP server would when matching the URI.
+func templateRequest(uri string, arguments map[string]interface{}) mcp.ReadResourceRequest {
+	var req mcp.ReadResourceRequest
+	req.Params.URI = uri
+	req.Params.Arguments = arguments
+	return req
+}
+
+// DebugContainerHandler gathers the inspect output, logs and filesystem
+// changes of the container.
+func DebugContainerHandler(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	container, err := promptArgument(req, "container", params.ContainerFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []mcp.PromptMessage{userMessage(mcp.NewTextContent(fmt.Sprintf(
+		"Container %s is crashing. From its inspect output, logs and filesystem changes below, find the "+
+			"root cause: check the exit code, OOM kill, health check and restart count in its state, the "+
+			"errors in its logs, and the configuration, mounts and files it depends on. Then suggest a fix.",
+		container)))}
+
+	arguments := map[string]interface{}{"id": container}
+	inspect, err := containerObject.inspectHandler()(ctx,
+		templateRequest(ResourcePrefix+"containers/"+container, arguments))
+	if err != nil {
+		return nil, err
+	}
+	messages = append(messages, resourceMessages(inspect)...)
+
+	logs, err := containerLogsHandler(ctx, templateRequest(ResourcePrefix+"containers/"+container+"/logs", arguments))
+	if err != nil {
+		messages = append(messages, outputMessage("Logs", nil, err))
+	} else {
+		messages = append(messages, resourceMessages(logs)...)
+	}
+
+	diff, err := runDocker(ctx, "diff", container)
+	messages = append(messages, outputMessage("Filesystem changes (docker diff)", diff, err))
+
+	return mcp.NewGetPromptResult(fmt.Sprintf("Debug crashing container %s", container), messages), nil
+}
+
+// HardenImageHandler gathers the history, vulnerability summary and
+// package counts of the image.
+func HardenImageHandler(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	image, err := promptArgument(req, "image", params.ImageFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []mcp.PromptMessage{userMessage(mcp.NewTextContent(fmt.Sprintf(
+		"Suggest how to harden image %s. From its history, vulnerabilities and packages below, look for a "+
+			"smaller or more recent base image, packages which can be removed or upgraded to fix the "+
+			"vulnerabilities, secrets and build tools left in the layers, and instructions running as root. "+
+			"Give the Dockerfile changes, most impactful first.",
+		image)))}
+
+	history, err := runDocker(ctx, "history", "--no-trunc", "--format", "{{.Size}}\t{{.CreatedBy}}", image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to show history for image %s: %w", image, err)
+	}
+	messages = append(messages, outputMessage("History (size and instruction of each layer)", history, nil))
+
+	var summary []byte
+	report, _, err := trivy.ScanReport(ctx, "image", image, trivy.ScanOptions{})
+	if err == nil {
+		report.ArtifactName = image
+		summary, err = json.MarshalIndent(report.Summarize(20), "", "  ")
+	}
+	messages = append(messages, outputMessage("Vulnerabilities and secrets (trivy)", summary, err))
+
+	var counts []byte
+	sbomBytes, generator, err := generateSBOM(ctx, image, SPDXFormat, "auto")
+	if err == nil {
+		var doc *sbom.Document
+		if doc, err = sbom.Parse(sbomBytes); err == nil {
+			counts, err = json.MarshalIndent(doc.Count(), "", "  ")
+		}
+	}
+	title := "Packages per ecosystem (SBOM)"
+	if generator != "" {
+		title = fmt.Sprintf("Packages per ecosystem (SBOM by %s)", generator)
+	}
+	messages = append(messages, outputMessage(title, counts, err))
+
+	return mcp.NewGetPromptResult(fmt.Sprintf("Harden image %s", image), messages), nil
+}
+
+// ExplainImageSizeHandler gathers the total size of the image and the
+// size of each of its layers.
+func ExplainImageSizeHandler(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	image, err := promptArgument(req, "image", params.ImageFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	sizeBytes, err := runDocker(ctx, "image", "inspect", "--format", "{{.Size}}", image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	size, err := strconv.ParseInt(string(bytes.TrimSpace(sizeBytes)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse size of image %s: %w", image, err)
+	}
+
+	history, err := runDocker(ctx, "history", "--no-trunc", "--format", "{{.Size}}\t{{.CreatedSince}}\t{{.CreatedBy}}", image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to show history for image %s: %w", image, err)
+	}
+
+	messages := []mcp.PromptMessage{
+		userMessage(mcp.NewTextContent(fmt.Sprintf(
+			"Explain the size of image %s, %.1f MB in total. From the size and instruction of each layer "+
+				"below, newest first, point out the layers which weigh the most and why, e.g. package "+
+				"caches, build dependencies or files copied then deleted in a later layer, and how to "+
+				"shrink them, e.g. with multi-stage builds or by cleaning up in the same RUN instruction.",
+			image, float64(size)/1e6))),
+		outputMessage("Layers (size, age and instruction)", history, nil),
+	}
+	return mcp.NewGetPromptResult(fmt.Sprintf("Explain the size of image %s", image), messages), nil
+}
+
+// WithPrompts adds the docker workflow prompts to the MCP server
+func WithPrompts(s *server.MCPServer) *server.MCPServer {
+	s.AddPrompt(DebugContainerPrompt, DebugContainerHandler)
+	s.AddPrompt(HardenImagePrompt, HardenImageHandler)
+	s.AddPrompt(ExplainImageSizePrompt, ExplainImageSizeHandler)
+	return s
+}
@@ -5,7 +5,10 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strings"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -16,46 +19,68 @@ var PSTool = mcp.NewTool("docker_ps",
 	mcp.WithString("filter",
 		mcp.Description("Filter output based on conditions provided"),
 	),
-	mcp.WithString("all",
+	mcp.WithBoolean("all",
 		mcp.Description("Show all containers (default shows just running)"),
+		mcp.DefaultBool(false),
 	),
 	mcp.WithString("format",
 		mcp.Description("Format the output using a custom template"),
 	),
-	mcp.WithString("latest",
+	mcp.WithBoolean("latest",
 		mcp.Description("Show the latest created container (includes all states)"),
+		mcp.DefaultBool(false),
 	),
-	mcp.WithString("no-trunc",
+	mcp.WithBoolean("no-trunc",
 		mcp.Description("Don't truncate output"),
+		mcp.DefaultBool(false),
 	),
+	output.CursorOption(),
+	output.PageSizeOption(),
+	output.StoreOption(),
 )
 
 // PSHandler is the handler function that handles ps requests, to list
 // all running containers, while using docker cli tool.
 func PSHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	args := []string{"ps"}
-	filter, exist := req.Params.Arguments["filter"]
-	if exist {
-		args = append(args, "--filter", filter.(string))
+	filter, err := params.OptionalString(req, "filter", "")
+	if err != nil {
+		return nil, err
+	}
+	all, err := params.Bool(req, "all", false)
+	if err != nil {
+		return nil, err
+	}
+	format, err := params.OptionalString(req, "format", "")
+	if err != nil {
+		return nil, err
+	}
+	latest, err := params.Bool(req, "latest", false)
+	if err != nil {
+		return nil, err
+	}
+	noTrunc, err := params.Bool(req, "no-trunc", false)
+	if err != nil {
+		return nil, err
+	}
+	page, err := output.ParsePage(req)
+	if err != nil {
+		return nil, err
 	}
 
-	_, exist = req.Params.Arguments["all"]
-	if exist {
+	args := []string{"ps"}
+	if filter != "" {
+		args = append(args, "--filter", filter)
+	}
+	if all {
 		args = append(args, "--all")
 	}
-
-	format, exist := req.Params.Arguments["format"]
-	if exist {
-		args = append(args, "--format", format.(string))
+	if format != "" {
+		args = append(args, "--format", format)
 	}
-
-	_, exist = req.Params.Arguments["latest"]
-	if exist {
+	if latest {
 		args = append(args, "--latest")
 	}
-
-	_, exist = req.Params.Arguments["no-trunc"]
-	if exist {
+	if noTrunc {
 		args = append(args, "--no-trunc")
 	}
 
@@ -66,13 +91,15 @@ func PSHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResul
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w \n %s", err, stderr.String())
 	}
-	result := string(outBytes)
+	// Custom formats only print a header when they're tables.
+	header := format == "" || strings.HasPrefix(format, "table")
+	result, next := output.PaginateLines(string(outBytes), page, header)
 
-	return mcp.NewToolResultText(fmt.Sprintf("%s", result)), nil
+	return output.WithNextCursor(mcp.NewToolResultText(result), next), nil
 }
 
 // WithPSTool is a convenience function to add the Docker ps tool to the MCP server.
 func WithPSTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(PSTool, PSHandler)
+	s.AddTool(PSTool, params.Validate(PSTool, PSHandler))
 	return s
 }
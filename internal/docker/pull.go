@@ -3,9 +3,18 @@ package docker
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
 	"os/exec"
+	"strings"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/progress"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -15,15 +24,187 @@ var PullTool = mcp.NewTool("docker_pull",
 	mcp.WithString("image",
 		mcp.Required(),
 		mcp.Description("The name of the image to pull"),
+		params.Format(params.ImageFormat),
 	),
+	output.StoreOption(),
 )
 
-// PullHandler is the handler function that handles pull requests
+// pullMessage is a message of the JSON stream of an Engine API pull.
+type pullMessage struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	ProgressDetail struct {
+		Current int64 `json:"current"`
+		Total   int64 `json:"total"`
+	} `json:"progressDetail"`
+	Error string `json:"error"`
+}
+
+// layerProgress is the progress of the download and extraction of a layer.
+type layerProgress struct {
+	downloaded, extracted, size int64
+	complete                    bool
+}
+
+// pullProgress aggregates the progress of the layers of a pull.
+type pullProgress struct {
+	layers map[string]*layerProgress
+	order  []string
+}
+
+// update records the progress carried by the message.
+func (p *pullProgress) update(msg pullMessage) {
+	// The image itself is reported under its tag, e.g. when the pull
+	// starts, but isn't a layer.
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from ") {
+		return
+	}
+	layer, exist := p.layers[msg.ID]
+	if !exist {
+		layer = &layerProgress{}
+		p.layers[msg.ID] = layer
+		p.order = append(p.order, msg.ID)
+	}
+	if msg.ProgressDetail.Total > 0 {
+		layer.size = msg.ProgressDetail.Total
+	}
+	switch msg.Status {
+	case "Downloading":
+		layer.downloaded = msg.ProgressDetail.Current
+	case "Download complete", "Verifying Checksum":
+		layer.downloaded = layer.size
+	case "Extracting":
+		layer.downloaded = layer.size
+		layer.extracted = msg.ProgressDetail.Current
+	case "Pull complete":
+		layer.downloaded, layer.extracted = layer.size, layer.size
+		layer.complete = true
+	case "Already exists":
+		layer.complete = true
+	}
+}
+
+// percent returns the progress of the pull as a percentage, along with a
+// description of it. Each layer weighs the same, as their sizes are only
+// known once they start downloading, while they're all listed when the
+// pull starts. Downloading and extracting a layer weigh the same.
+func (p *pullProgress) percent() (float64, string) {
+	if len(p.order) == 0 {
+		return 0, ""
+	}
+	var done float64
+	var downloaded, size int64
+	complete := 0
+	for _, id := range p.order {
+		layer := p.layers[id]
+		switch {
+		case layer.complete:
+			complete++
+			done++
+		case layer.size > 0:
+			done += float64(layer.downloaded+layer.extracted) / float64(2*layer.size)
+		}
+		downloaded += layer.downloaded
+		size += layer.size
+	}
+	message := fmt.Sprintf("%d/%d layers pulled, %.1f/%.1f MB downloaded",
+		complete, len(p.order), float64(downloaded)/1e6, float64(size)/1e6)
+	return math.Floor(1000*done/float64(len(p.order))) / 10, message
+}
+
+// splitReference splits the image reference into the repository and the
+// tag or digest, as the Engine API pulls every tag when it's omitted.
+func splitReference(image string) (string, string) {
+	if repository, digest, found := strings.Cut(image, "@"); found {
+		return repository, digest
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
+// pullWithProgress pulls the image through the Engine API, reporting the
+// progress of its layers, and returns the final status messages, like the
+// cli does.
+func pullWithProgress(ctx context.Context, image string, reporter *progress.Reporter) (string, error) {
+	client, base, err := engineClient()
+	if err != nil {
+		return "", err
+	}
+	repository, tag := splitReference(image)
+	query := url.Values{"fromImage": {repository}, "tag": {tag}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/images/create?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	auth, err := registryAuthHeader(ctx, repository)
+	if err != nil {
+		return "", err
+	}
+	if auth != "" {
+		req.Header.Set("X-Registry-Auth", auth)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("engine API returned %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	var status strings.Builder
+	state := pullProgress{layers: make(map[string]*layerProgress)}
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var msg pullMessage
+		if err := decoder.Decode(&msg); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		if msg.Error != "" {
+			return "", fmt.Errorf("%s", msg.Error)
+		}
+		// Messages without ID are about the whole image, e.g. its digest.
+		if msg.ID == "" && msg.Status != "" {
+			status.WriteString(msg.Status + "\n")
+			continue
+		}
+		state.update(msg)
+		if percent, message := state.percent(); percent > 0 {
+			reporter.Report(percent, 100, message)
+		}
+	}
+	return status.String(), nil
+}
+
+// PullHandler is the handler function that handles pull requests. When the
+// client asks for progress, the image is pulled through the Engine API to
+// report the progress of its layers, with the registry credentials of the
+// cli, falling back to the cli, which knows the daemon configuration, when
+// it fails.
 func PullHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	image := req.Params.Arguments["image"].(string)
+	image, err := params.String(req, "image")
+	if err != nil {
+		return nil, err
+	}
+
+	if reporter := progress.FromContext(ctx); reporter != nil {
+		// The progress of the pull is a percentage from the start, which
+		// the stages then stay on.
+		reporter.Report(0, 100, fmt.Sprintf("Pulling %s", image))
+		result, err := pullWithProgress(ctx, image, reporter)
+		if err == nil {
+			return mcp.NewToolResultText(result), nil
+		}
+		reporter.Stage(fmt.Sprintf("Pulling %s with the docker cli, as the Engine API pull failed: %v", image, err))
+	}
 
 	var stderr bytes.Buffer
-	pullCmd := exec.Command("docker", "pull", image)
+	pullCmd := exec.CommandContext(ctx, "docker", "pull", image)
 	pullCmd.Stderr = &stderr
 	outBytes, err := pullCmd.Output()
 	if err != nil {
@@ -36,6 +217,6 @@ func PullHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolRes
 
 // WithPullTool adds the pull tool to the MCP server
 func WithPullTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(PullTool, PullHandler)
+	s.AddTool(PullTool, params.Validate(PullTool, PullHandler))
 	return s
 }
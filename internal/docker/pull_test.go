@@ -0,0 +1,85 @@
+package docker
+
+import "testing"
+
+func TestPullProgressPercent(t *testing.T) {
+	message := func(id, status string, current, total int64) pullMessage {
+		msg := pullMessage{ID: id, Status: status}
+		msg.ProgressDetail.Current, msg.ProgressDetail.Total = current, total
+		return msg
+	}
+
+	tests := []struct {
+		name     string
+		messages []pullMessage
+		want     float64
+		message  string
+	}{
+		{"no layers", nil, 0, ""},
+		{"pull started", []pullMessage{
+			message("latest", "Pulling from library/alpine", 0, 0),
+		}, 0, ""},
+		{"layers listed", []pullMessage{
+			message("a", "Pulling fs layer", 0, 0),
+			message("b", "Pulling fs layer", 0, 0),
+		}, 0, "0/2 layers pulled, 0.0/0.0 MB downloaded"},
+		{"half downloaded", []pullMessage{
+			message("a", "Pulling fs layer", 0, 0),
+			message("b", "Pulling fs layer", 0, 0),
+			message("a", "Downloading", 1e6, 2e6),
+		}, 12.5, "0/2 layers pulled, 1.0/2.0 MB downloaded"},
+		{"downloaded", []pullMessage{
+			message("a", "Pulling fs layer", 0, 0),
+			message("b", "Pulling fs layer", 0, 0),
+			message("a", "Downloading", 1e6, 2e6),
+			message("a", "Download complete", 0, 0),
+		}, 25, "0/2 layers pulled, 2.0/2.0 MB downloaded"},
+		{"half extracted", []pullMessage{
+			message("a", "Pulling fs layer", 0, 0),
+			message("b", "Pulling fs layer", 0, 0),
+			message("a", "Downloading", 1e6, 2e6),
+			message("a", "Extracting", 1e6, 2e6),
+		}, 37.5, "0/2 layers pulled, 2.0/2.0 MB downloaded"},
+		{"one layer pulled, the other existing", []pullMessage{
+			message("a", "Pulling fs layer", 0, 0),
+			message("b", "Already exists", 0, 0),
+			message("a", "Downloading", 1e6, 2e6),
+			message("a", "Pull complete", 0, 0),
+		}, 100, "2/2 layers pulled, 2.0/2.0 MB downloaded"},
+		{"rounded down", []pullMessage{
+			message("a", "Pulling fs layer", 0, 0),
+			message("b", "Pulling fs layer", 0, 0),
+			message("c", "Already exists", 0, 0),
+		}, 33.3, "1/3 layers pulled, 0.0/0.0 MB downloaded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &pullProgress{layers: make(map[string]*layerProgress)}
+			for _, msg := range tt.messages {
+				p.update(msg)
+			}
+			got, message := p.percent()
+			if got != tt.want || message != tt.message {
+				t.Errorf("percent() = %v, %q, want %v, %q", got, message, tt.want, tt.message)
+			}
+		})
+	}
+}
+
+func TestSplitReference(t *testing.T) {
+	tests := []struct {
+		image, repository, tag string
+	}{
+		{"alpine", "alpine", "latest"},
+		{"alpine:3.19", "alpine", "3.19"},
+		{"localhost:5000/app", "localhost:5000/app", "latest"},
+		{"localhost:5000/app:v1", "localhost:5000/app", "v1"},
+		{"alpine@sha256:abc", "alpine", "sha256:abc"},
+	}
+	for _, tt := range tests {
+		repository, tag := splitReference(tt.image)
+		if repository != tt.repository || tag != tt.tag {
+			t.Errorf("splitReference(%q) = %q, %q, want %q, %q", tt.image, repository, tag, tt.repository, tt.tag)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the resources exposing the docker state, i.e.
+// listings of the containers, images, volumes and networks, and the
+// inspect JSON or logs of each one, so clients can attach it as context.
+
+// ResourcePrefix is the prefix of the URIs of the docker resources.
+const ResourcePrefix = "docker://"
+
+// logsTail is the number of log lines returned by the logs resource.
+const logsTail = 500
+
+// dockerObject describes a kind of docker object exposed as resources.
+type dockerObject struct {
+	// kind is the plural name used in the URIs, e.g. containers.
+	kind string
+	// command is the docker command managing the objects, e.g. container.
+	command string
+	// variable is the URI template variable identifying an object.
+	variable string
+	// format is the format of the IDs or names of the objects.
+	format string
+	// uriName returns the ID or name of a listed object used in its URI.
+	uriName func(item map[string]interface{}) string
+}
+
+var containerObject = dockerObject{
+	kind:     "containers",
+	command:  "container",
+	variable: "{id}",
+	format:   params.ContainerFormat,
+	uriName:  func(item map[string]interface{}) string { return stringField(item, "ID") },
+}
+
+var dockerObjects = []dockerObject{
+	containerObject,
+	{
+		kind:    "images",
+		command: "image",
+		// Image references hold slashes, so they're matched with a
+		// reserved expansion.
+		variable: "{+ref}",
+		format:   params.ImageFormat,
+		uriName: func(item map[string]interface{}) string {
+			repository, tag := stringField(item, "Repository"), stringField(item, "Tag")
+			if repository == "" || repository == "<none>" || tag == "<none>" {
+				return stringField(item, "ID")
+			}
+			return repository + ":" + tag
+		},
+	},
+	{
+		kind:     "volumes",
+		command:  "volume",
+		variable: "{name}",
+		format:   params.NameFormat,
+		uriName:  func(item map[string]interface{}) string { return stringField(item, "Name") },
+	},
+	{
+		kind:     "networks",
+		command:  "network",
+		variable: "{name}",
+		format:   params.NameFormat,
+		uriName:  func(item map[string]interface{}) string { return stringField(item, "Name") },
+	},
+}
+
+func stringField(item map[string]interface{}, key string) string {
+	value, _ := item[key].(string)
+	return value
+}
+
+// listHandler returns the handler of the listing of the objects, where
+// each object carries the URI of its inspect resource.
+func (o dockerObject) listHandler() server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		args := []string{o.command, "ls", "--format", "{{json .}}"}
+		if o.command == "container" {
+			args = append(args, "--all")
+		}
+		outBytes, err := runDocker(ctx, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", o.kind, err)
+		}
+
+		items := []map[string]interface{}{}
+		scanner := bufio.NewScanner(bytes.NewReader(outBytes))
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var item map[string]interface{}
+			if err := json.Unmarshal(line, &item); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", o.kind, err)
+			}
+			if name := o.uriName(item); name != "" {
+				item["uri"] = ResourcePrefix + o.kind + "/" + name
+			}
+			items = append(items, item)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", o.kind, err)
+		}
+		return jsonContents(req.Params.URI, items)
+	}
+}
+
+// objectName returns the ID or name of the object from the URI variables.
+func (o dockerObject) objectName(req mcp.ReadResourceRequest) (string, error) {
+	variable := strings.Trim(o.variable, "{+}")
+	value, _ := req.Params.Arguments[variable].(string)
+	// Clients may escape the slashes and colons of image references.
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+	if err := params.CheckFormat(variable, o.format, value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// inspectHandler returns the handler of the inspect JSON of an object.
+func (o dockerObject) inspectHandler() server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		name, err := o.objectName(req)
+		if err != nil {
+			return nil, err
+		}
+		outBytes, err := runDocker(ctx, o.command, "inspect", name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", name, err)
+		}
+		var objects []json.RawMessage
+		if err := json.Unmarshal(outBytes, &objects); err != nil || len(objects) == 0 {
+			return nil, fmt.Errorf("failed to parse inspect output of %s", name)
+		}
+		return jsonContents(req.Params.URI, objects[0])
+	}
+}
+
+// containerLogsHandler returns the last lines of the logs of a container,
+// with both its standard output and error.
+func containerLogsHandler(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	name, err := containerObject.objectName(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs bytes.Buffer
+	logsCmd := exec.CommandContext(ctx, "docker", "logs", "--timestamps", "--tail", strconv.Itoa(logsTail), name)
+	logsCmd.Stdout = &logs
+	logsCmd.Stderr = &logs
+	if err := logsCmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to read logs of %s: %w \n %s", name, err, logs.String())
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      req.Params.URI,
+		MIMEType: "text/plain",
+		Text:     logs.String(),
+	}}, nil
+}
+
+func jsonContents(uri string, value interface{}) ([]mcp.ResourceContents, error) {
+	valueBytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", uri, err)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(valueBytes),
+	}}, nil
+}
+
+// WithResources adds the docker resources to the MCP server
+func WithResources(s *server.MCPServer) *server.MCPServer {
+	for _, o := range dockerObjects {
+		s.AddResource(mcp.NewResource(ResourcePrefix+o.kind, "Docker "+o.kind,
+			mcp.WithResourceDescription(fmt.Sprintf("The list of the docker %s, each with the URI of its resource", o.kind)),
+			mcp.WithMIMEType("application/json"),
+		), o.listHandler())
+		s.AddResourceTemplate(mcp.NewResourceTemplate(ResourcePrefix+o.kind+"/"+o.variable, "Docker "+o.command,
+			mcp.WithTemplateDescription(fmt.Sprintf("The inspect JSON of a docker %s, by ID or name", o.command)),
+			mcp.WithTemplateMIMEType("application/json"),
+		), o.inspectHandler())
+	}
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(ResourcePrefix+"containers/{id}/logs", "Docker container logs",
+		mcp.WithTemplateDescription(fmt.Sprintf("The last %d lines of the logs of a docker container, with timestamps", logsTail)),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), containerLogsHandler)
+	return s
+}
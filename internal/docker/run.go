@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -16,6 +18,7 @@ var RunTool = mcp.NewTool("docker_run",
 	mcp.WithString("image",
 		mcp.Required(),
 		mcp.Description("The name of the image to run"),
+		params.Format(params.ImageFormat),
 	),
 	mcp.WithString("command",
 		mcp.Description("The command to run in the container"),
@@ -23,14 +26,17 @@ var RunTool = mcp.NewTool("docker_run",
 	mcp.WithString("name",
 		mcp.Description("The name to assign to the container"),
 	),
-	mcp.WithString("interactive",
+	mcp.WithBoolean("interactive",
 		mcp.Description("Run the container in interactive mode"),
+		mcp.DefaultBool(false),
 	),
-	mcp.WithString("rm",
+	mcp.WithBoolean("rm",
 		mcp.Description("Automatically remove the container when it exits"),
+		mcp.DefaultBool(false),
 	),
-	mcp.WithString("detach",
+	mcp.WithBoolean("detach",
 		mcp.Description("Run the container in detached mode"),
+		mcp.DefaultBool(false),
 	),
 	mcp.WithString("workdir",
 		mcp.Description("Set the working directory inside the container"),
@@ -38,39 +44,72 @@ var RunTool = mcp.NewTool("docker_run",
 	mcp.WithString("network",
 		mcp.Description("Connect the container to a network"),
 	),
-	mcp.WithString("env",
-		mcp.Description("Set environment variables in the container"),
+	mcp.WithArray("env",
+		mcp.Description("Set environment variables in the container (KEY=VALUE)"),
+		params.StringArray(),
 	),
-	mcp.WithString("volume",
-		mcp.Description("Mount a volume into the container"),
+	mcp.WithArray("volume",
+		mcp.Description("Mount volumes into the container (SOURCE:TARGET[:OPTIONS])"),
+		params.StringArray(),
 	),
+	output.StoreOption(),
 )
 
 // RunHandler is the handler function that handles run requests
 func RunHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	image := req.Params.Arguments["image"].(string)
-	command, _ := req.Params.Arguments["command"].(string)
-	name, _ := req.Params.Arguments["name"].(string)
-	interactive, _ := req.Params.Arguments["interactive"].(string)
-	rm, _ := req.Params.Arguments["rm"].(string)
-	detach, _ := req.Params.Arguments["detach"].(string)
-	workdir, _ := req.Params.Arguments["workdir"].(string)
-	network, _ := req.Params.Arguments["network"].(string)
-	env, _ := req.Params.Arguments["env"].(string)
-	volume, _ := req.Params.Arguments["volume"].(string)
+	image, err := params.String(req, "image")
+	if err != nil {
+		return nil, err
+	}
+	command, err := params.OptionalString(req, "command", "")
+	if err != nil {
+		return nil, err
+	}
+	name, err := params.OptionalString(req, "name", "")
+	if err != nil {
+		return nil, err
+	}
+	interactive, err := params.Bool(req, "interactive", false)
+	if err != nil {
+		return nil, err
+	}
+	rm, err := params.Bool(req, "rm", false)
+	if err != nil {
+		return nil, err
+	}
+	detach, err := params.Bool(req, "detach", false)
+	if err != nil {
+		return nil, err
+	}
+	workdir, err := params.OptionalString(req, "workdir", "")
+	if err != nil {
+		return nil, err
+	}
+	network, err := params.OptionalString(req, "network", "")
+	if err != nil {
+		return nil, err
+	}
+	envs, err := params.StringSlice(req, "env")
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := params.StringSlice(req, "volume")
+	if err != nil {
+		return nil, err
+	}
 
 	args := []string{"run"}
 
 	if name != "" {
 		args = append(args, "--name", name)
 	}
-	if interactive != "" {
+	if interactive {
 		args = append(args, "-it")
 	}
-	if rm != "" {
+	if rm {
 		args = append(args, "--rm")
 	}
-	if detach != "" {
+	if detach {
 		args = append(args, "-d")
 	}
 	if workdir != "" {
@@ -79,10 +118,10 @@ func RunHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResu
 	if network != "" {
 		args = append(args, "--network", network)
 	}
-	if env != "" {
+	for _, env := range envs {
 		args = append(args, "--env", env)
 	}
-	if volume != "" {
+	for _, volume := range volumes {
 		args = append(args, "--volume", volume)
 	}
 
@@ -127,6 +166,6 @@ func RunHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResu
 
 // WithRunTool adds the RunTool to the MCP server
 func WithRunTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(RunTool, RunHandler)
+	s.AddTool(RunTool, params.Validate(RunTool, RunHandler))
 	return s
 }
@@ -3,57 +3,173 @@ package docker
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/progress"
+	"github.com/mark3labs/mcp-docker/internal/trivy"
+	"github.com/mark3labs/mcp-docker/internal/workspace"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// SBOM formats, which are produced the same way by every generator.
+const (
+	SPDXFormat      = "spdx-json"
+	CycloneDXFormat = "cyclonedx-json"
+)
+
+// SBOM generators, in the order they are picked in when detecting them.
+const (
+	DockerGenerator = "docker"
+	SyftGenerator   = "syft"
+	TrivyGenerator  = "trivy"
+)
+
 var SBOMTool = mcp.NewTool("docker_sbom",
 	mcp.WithDescription("Generates a Software Bill of Materials (SBOM) for a Docker image"),
 	mcp.WithString("image",
 		mcp.Required(),
 		mcp.Description("The name of the image to generate SBOM for"),
+		params.Format(params.ImageFormat),
 	),
 	mcp.WithString("format",
-		mcp.Description("The format of the SBOM (e.g., spdx, cyclonedx)"),
+		mcp.Description("The format of the SBOM"),
+		mcp.Enum(SPDXFormat, CycloneDXFormat),
+		mcp.DefaultString(SPDXFormat),
+	),
+	mcp.WithString("generator",
+		mcp.Description("The tool generating the SBOM, auto picks the first available of docker sbom, syft and trivy"),
+		mcp.Enum("auto", DockerGenerator, SyftGenerator, TrivyGenerator),
+		mcp.DefaultString("auto"),
 	),
 	mcp.WithString("output",
-		mcp.Description("The output file for the SBOM"),
+		mcp.Description("The output file for the SBOM, relative to the workspace"),
 	),
+	output.StoreOption(),
 )
 
-// SBOMHandler is the handler function that handles SBOM requests
-// and generates a Software Bill of Materials (SBOM) for a Docker image.
-func SBOMHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	image := req.Params.Arguments["image"].(string)
-	format, _ := req.Params.Arguments["format"].(string)
-	output, _ := req.Params.Arguments["output"].(string)
+// errNoSBOMGenerator is returned when none of the SBOM generators is
+// installed.
+var errNoSBOMGenerator = errors.New("no SBOM generator available, install the docker sbom plugin, syft or trivy")
 
-	args := []string{"sbom", image}
+// sbomGeneratorAvailable reports whether the generator is installed.
+func sbomGeneratorAvailable(ctx context.Context, generator string) bool {
+	switch generator {
+	case DockerGenerator:
+		// The plugin isn't a binary on the path, so it's probed by
+		// running it.
+		return exec.CommandContext(ctx, "docker", "sbom", "--version").Run() == nil
+	default:
+		_, err := exec.LookPath(generator)
+		return err == nil
+	}
+}
 
-	if format != "" {
-		args = append(args, "--format", format)
+// detectSBOMGenerator returns the first available SBOM generator.
+func detectSBOMGenerator(ctx context.Context) (string, error) {
+	for _, generator := range []string{DockerGenerator, SyftGenerator, TrivyGenerator} {
+		if sbomGeneratorAvailable(ctx, generator) {
+			return generator, nil
+		}
 	}
-	if output != "" {
-		args = append(args, "--output", output)
+	return "", errNoSBOMGenerator
+}
+
+// generateSBOM generates the SBOM of the image in the given format, with
+// the given generator or the first available one when it's auto, and
+// returns the document along with the generator used.
+func generateSBOM(ctx context.Context, image, format, generator string) ([]byte, string, error) {
+	reporter := progress.FromContext(ctx)
+	if generator == "" || generator == "auto" {
+		reporter.Stage("Detecting the SBOM generator")
+		detected, err := detectSBOMGenerator(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		generator = detected
+	} else if !sbomGeneratorAvailable(ctx, generator) {
+		return nil, "", fmt.Errorf("SBOM generator %s is not available", generator)
+	}
+
+	reporter.Stage(fmt.Sprintf("Generating the %s SBOM of %s with %s", format, image, generator))
+	var sbomCmd *exec.Cmd
+	switch generator {
+	case DockerGenerator:
+		sbomCmd = exec.CommandContext(ctx, "docker", "sbom", "--format", format, image)
+	case SyftGenerator:
+		sbomCmd = exec.CommandContext(ctx, "syft", image, "--quiet", "--output", format)
+	case TrivyGenerator:
+		// trivy names the CycloneDX JSON format cyclonedx, and runs with the
+		// serve time configuration of the trivy tools.
+		trivyFormat := format
+		if format == CycloneDXFormat {
+			trivyFormat = "cyclonedx"
+		}
+		outBytes, err := trivy.Scan(ctx, "image", image, trivyFormat, trivy.ScanOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to generate SBOM for image %s: %w", image, err)
+		}
+		return outBytes, generator, nil
+	default:
+		return nil, "", fmt.Errorf("%w: unknown SBOM generator %q", params.ErrInvalidParams, generator)
 	}
 
 	var stderr bytes.Buffer
-	sbomCmd := exec.Command("docker", args...)
 	sbomCmd.Stderr = &stderr
 	outBytes, err := sbomCmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate SBOM for image %s: %w \n %s", image, err, stderr.String())
+		return nil, "", fmt.Errorf("failed to generate SBOM for image %s: %w \n %s", image, err, stderr.String())
+	}
+	return outBytes, generator, nil
+}
+
+// SBOMHandler is the handler function that handles SBOM requests
+// and generates a Software Bill of Materials (SBOM) for a Docker image.
+func SBOMHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	image, err := params.String(req, "image")
+	if err != nil {
+		return nil, err
+	}
+	format, err := params.OptionalString(req, "format", SPDXFormat)
+	if err != nil {
+		return nil, err
+	}
+	generator, err := params.OptionalString(req, "generator", "auto")
+	if err != nil {
+		return nil, err
+	}
+	output, err := params.OptionalString(req, "output", "")
+	if err != nil {
+		return nil, err
+	}
+
+	sbomBytes, generator, err := generateSBOM(ctx, image, format, generator)
+	if err != nil {
+		return nil, err
+	}
+
+	if output != "" {
+		progress.FromContext(ctx).Stage(fmt.Sprintf("Writing the SBOM to %s", output))
+		rel, err := workspace.WriteFile(output, sbomBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write SBOM: %w", err)
+		}
+		result := mcp.NewToolResultText(fmt.Sprintf("%s SBOM generated by %s", format, generator))
+		result.Content = append(result.Content, workspace.Resource(rel, "application/json", sbomBytes)...)
+		return result, nil
 	}
-	result := string(outBytes)
 
-	return mcp.NewToolResultText(fmt.Sprintf("%s", result)), nil
+	result := mcp.NewToolResultText(string(sbomBytes))
+	result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf("%s SBOM generated by %s", format, generator)))
+	return result, nil
 }
 
 // WithSBOMTool adds the SBOMTool to the MCP server
 func WithSBOMTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(SBOMTool, SBOMHandler)
+	s.AddTool(SBOMTool, params.Validate(SBOMTool, SBOMHandler))
 	return s
 }
@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/sbom"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the docker_sbom_diff tool, which compares the
+// packages of two images, e.g. to review a base image upgrade.
+
+var SBOMDiffTool = mcp.NewTool("docker_sbom_diff",
+	mcp.WithDescription("Compare the SBOMs of two Docker images, reporting the added, removed and version-changed packages"),
+	mcp.WithString("base",
+		mcp.Description("The image to compare from, e.g. the current base image, unless base-file is given"),
+		params.Format(params.ImageFormat),
+	),
+	mcp.WithString("target",
+		mcp.Description("The image to compare to, e.g. the upgraded base image, unless target-file is given"),
+		params.Format(params.ImageFormat),
+	),
+	mcp.WithString("base-file",
+		mcp.Description("The path of an SPDX or CycloneDX JSON document, relative to the workspace, to compare from instead of generating one"),
+	),
+	mcp.WithString("target-file",
+		mcp.Description("The path of an SPDX or CycloneDX JSON document, relative to the workspace, to compare to instead of generating one"),
+	),
+	mcp.WithString("ecosystem",
+		mcp.Description("Only compare the packages of this ecosystem, i.e. package URL type, e.g. npm, maven or deb"),
+	),
+	mcp.WithString("generator",
+		mcp.Description("The tool generating the SBOMs, auto picks the first available of docker sbom, syft and trivy"),
+		mcp.Enum("auto", DockerGenerator, SyftGenerator, TrivyGenerator),
+		mcp.DefaultString("auto"),
+	),
+	output.StoreOption(),
+)
+
+// SBOMDiffResult is the result of the SBOM diff tool.
+type SBOMDiffResult struct {
+	Base   string `json:"base"`
+	Target string `json:"target"`
+	sbom.Diff
+}
+
+// SBOMDiffHandler is the handler function that handles SBOM diff requests,
+// generating or loading the SBOMs of both images and comparing them.
+func SBOMDiffHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	base, err := params.OptionalString(req, "base", "")
+	if err != nil {
+		return nil, err
+	}
+	target, err := params.OptionalString(req, "target", "")
+	if err != nil {
+		return nil, err
+	}
+	baseFile, err := params.OptionalString(req, "base-file", "")
+	if err != nil {
+		return nil, err
+	}
+	targetFile, err := params.OptionalString(req, "target-file", "")
+	if err != nil {
+		return nil, err
+	}
+	ecosystem, err := params.OptionalString(req, "ecosystem", "")
+	if err != nil {
+		return nil, err
+	}
+	generator, err := params.OptionalString(req, "generator", "auto")
+	if err != nil {
+		return nil, err
+	}
+
+	baseDoc, err := loadSBOM(ctx, base, baseFile, generator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base SBOM: %w", err)
+	}
+	targetDoc, err := loadSBOM(ctx, target, targetFile, generator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load target SBOM: %w", err)
+	}
+	baseDoc.Packages = baseDoc.ByEcosystem(ecosystem)
+	targetDoc.Packages = targetDoc.ByEcosystem(ecosystem)
+
+	result := SBOMDiffResult{
+		Base:   base,
+		Target: target,
+		Diff:   sbom.DiffDocuments(baseDoc, targetDoc),
+	}
+	if baseFile != "" {
+		result.Base = baseFile
+	}
+	if targetFile != "" {
+		result.Target = targetFile
+	}
+	resultBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SBOM diff: %w", err)
+	}
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// WithSBOMDiffTool adds the SBOMDiffTool to the MCP server
+func WithSBOMDiffTool(s *server.MCPServer) *server.MCPServer {
+	s.AddTool(SBOMDiffTool, params.Validate(SBOMDiffTool, SBOMDiffHandler))
+	return s
+}
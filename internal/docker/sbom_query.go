@@ -0,0 +1,152 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/sbom"
+	"github.com/mark3labs/mcp-docker/internal/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the docker_sbom_query tool, which answers
+// questions about the packages of an image, e.g. whether it ships log4j,
+// from its parsed SBOM instead of returning the whole document.
+
+var SBOMQueryTool = mcp.NewTool("docker_sbom_query",
+	mcp.WithDescription("Query the packages listed by the SBOM of a Docker image or of an SBOM file"),
+	mcp.WithString("image",
+		mcp.Description("The name of the image to generate the SBOM of, unless file is given"),
+		params.Format(params.ImageFormat),
+	),
+	mcp.WithString("file",
+		mcp.Description("The path of an SPDX or CycloneDX JSON document, relative to the workspace, to query instead of generating one"),
+	),
+	mcp.WithString("query",
+		mcp.Required(),
+		mcp.Description("packages lists the packages, find searches them by name and version, licenses lists the licenses and count counts the packages"),
+		mcp.Enum("packages", "find", "licenses", "count"),
+	),
+	mcp.WithString("ecosystem",
+		mcp.Description("Only consider the packages of this ecosystem, i.e. package URL type, e.g. npm, maven or deb"),
+	),
+	mcp.WithString("name",
+		mcp.Description("The name, or part of the name, of the package to find, e.g. log4j"),
+	),
+	mcp.WithString("version-range",
+		mcp.Description("The versions of the package to find, e.g. \">=2.0.0, <2.17.1\""),
+	),
+	mcp.WithString("generator",
+		mcp.Description("The tool generating the SBOM, auto picks the first available of docker sbom, syft and trivy"),
+		mcp.Enum("auto", DockerGenerator, SyftGenerator, TrivyGenerator),
+		mcp.DefaultString("auto"),
+	),
+	output.CursorOption(),
+	output.PageSizeOption(),
+	output.StoreOption(),
+)
+
+// loadSBOM parses the SBOM of the file, when given, which is read from the
+// workspace, or generates the SBOM of the image.
+func loadSBOM(ctx context.Context, image, file, generator string) (*sbom.Document, error) {
+	var data []byte
+	var err error
+	switch {
+	case image != "" && file != "":
+		return nil, fmt.Errorf("%w: arguments \"image\" and \"file\" are mutually exclusive", params.ErrInvalidParams)
+	case file != "":
+		if data, err = workspace.ReadFile(file); err != nil {
+			return nil, fmt.Errorf("failed to read SBOM: %w", err)
+		}
+	case image != "":
+		if data, _, err = generateSBOM(ctx, image, SPDXFormat, generator); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: either argument \"image\" or \"file\" is required", params.ErrInvalidParams)
+	}
+	return sbom.Parse(data)
+}
+
+// SBOMQueryHandler is the handler function that handles SBOM queries,
+// parsing the SBOM and returning the answer to the query as JSON.
+func SBOMQueryHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	image, err := params.OptionalString(req, "image", "")
+	if err != nil {
+		return nil, err
+	}
+	file, err := params.OptionalString(req, "file", "")
+	if err != nil {
+		return nil, err
+	}
+	query, err := params.String(req, "query")
+	if err != nil {
+		return nil, err
+	}
+	ecosystem, err := params.OptionalString(req, "ecosystem", "")
+	if err != nil {
+		return nil, err
+	}
+	name, err := params.OptionalString(req, "name", "")
+	if err != nil {
+		return nil, err
+	}
+	versionRange, err := params.OptionalString(req, "version-range", "")
+	if err != nil {
+		return nil, err
+	}
+	generator, err := params.OptionalString(req, "generator", "auto")
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := output.ParsePage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	constraint, err := sbom.ParseConstraint(versionRange)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", params.ErrInvalidParams, err)
+	}
+	if query == "find" && name == "" {
+		return nil, fmt.Errorf("%w: argument \"name\" is required by the find query", params.ErrInvalidParams)
+	}
+
+	doc, err := loadSBOM(ctx, image, file, generator)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Packages = doc.ByEcosystem(ecosystem)
+
+	// The lists of packages and licenses are paginated.
+	var answer interface{}
+	var next string
+	switch query {
+	case "packages":
+		answer, next = output.Paginate(doc.Packages, page)
+	case "find":
+		answer, next = output.Paginate(doc.Find(name, constraint), page)
+	case "licenses":
+		answer, next = output.Paginate(doc.Licenses(), page)
+	case "count":
+		answer = doc.Count()
+	}
+
+	answerBytes, err := json.MarshalIndent(answer, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SBOM query result: %w", err)
+	}
+	return output.WithNextCursor(mcp.NewToolResultText(string(answerBytes)), next), nil
+}
+
+// WithSBOMQueryTool adds the SBOMQueryTool to the MCP server
+func WithSBOMQueryTool(s *server.MCPServer) *server.MCPServer {
+	s.AddTool(SBOMQueryTool, params.Validate(SBOMQueryTool, SBOMQueryHandler))
+	return s
+}
@@ -5,7 +5,11 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strconv"
+	"strings"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -22,26 +26,53 @@ var SearchTool = mcp.NewTool("docker_search",
 	mcp.WithString("format",
 		mcp.Description("The format to use for the output"),
 	),
-	mcp.WithString("limit",
+	mcp.WithNumber("limit",
 		mcp.Description("The maximum number of results to return"),
+		mcp.Min(1),
+		mcp.Max(100),
 	),
+	output.CursorOption(),
+	output.PageSizeOption(),
+	output.StoreOption(),
 )
 
 // SearchHandler is the handler function that handles search requests
 func SearchHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	query := req.Params.Arguments["query"].(string)
+	query, err := params.String(req, "query")
+	if err != nil {
+		return nil, err
+	}
+	filter, err := params.OptionalString(req, "filter", "")
+	if err != nil {
+		return nil, err
+	}
+	format, err := params.OptionalString(req, "format", "")
+	if err != nil {
+		return nil, err
+	}
+	// docker search has its own default limit, so the limit is only
+	// checked, and passed, when given.
+	limit, err := params.Int(req, "limit", 0)
+	if err != nil {
+		return nil, err
+	}
+	if req.Params.Arguments["limit"] != nil && (limit < 1 || limit > 100) {
+		return nil, fmt.Errorf("%w: argument %q must be between 1 and 100, got %d", params.ErrInvalidParams, "limit", limit)
+	}
+	page, err := output.ParsePage(req)
+	if err != nil {
+		return nil, err
+	}
+
 	args := []string{"search", query}
-	filteri, exist := req.Params.Arguments["filter"]
-	if exist {
-		args = append(args, "--filter", filteri.(string))
+	if filter != "" {
+		args = append(args, "--filter", filter)
 	}
-	format, exist := req.Params.Arguments["format"]
-	if exist {
-		args = append(args, "--format", format.(string))
+	if format != "" {
+		args = append(args, "--format", format)
 	}
-	limit, exist := req.Params.Arguments["limit"]
-	if exist {
-		args = append(args, "--limit", limit.(string))
+	if limit > 0 {
+		args = append(args, "--limit", strconv.Itoa(limit))
 	}
 
 	var stderr bytes.Buffer
@@ -51,13 +82,15 @@ func SearchHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolR
 	if err != nil {
 		return nil, fmt.Errorf("failed to search for images: %w \n %s", err, stderr.String())
 	}
-	result := string(outBytes)
+	// Custom formats only print a header when they're tables.
+	header := format == "" || strings.HasPrefix(format, "table")
+	result, next := output.PaginateLines(string(outBytes), page, header)
 
-	return mcp.NewToolResultText(fmt.Sprintf("%s", result)), nil
+	return output.WithNextCursor(mcp.NewToolResultText(result), next), nil
 }
 
 // WithSearchTool adds the search tool to the MCP server
 func WithSearchTool(s *server.MCPServer) *server.MCPServer {
-	s.AddTool(SearchTool, SearchHandler)
+	s.AddTool(SearchTool, params.Validate(SearchTool, SearchHandler))
 	return s
 }
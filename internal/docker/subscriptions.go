@@ -0,0 +1,274 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+)
+
+// In this file, we define the subscriptions to container resources. While
+// clients are subscribed, the docker events stream is watched, and when an
+// event changes the state, health or restart count of a subscribed
+// container, its resource is reported as updated.
+
+// reconnectDelay is the delay before watching the events stream again,
+// after it failed, e.g. when the docker daemon restarted.
+const reconnectDelay = 5 * time.Second
+
+// containerState is the part of the container state the subscribers are
+// notified of changes to.
+type containerState struct {
+	Status       string
+	Health       string
+	RestartCount int
+	Exists       bool
+}
+
+// watchedContainer is a container with subscribed resources.
+type watchedContainer struct {
+	uris  map[string]bool
+	state containerState
+}
+
+// ContainerWatcher tracks the subscriptions to container resources and
+// notifies the subscribers of the changes of the containers.
+type ContainerWatcher struct {
+	notify func(uri string)
+
+	mu         sync.Mutex
+	containers map[string]*watchedContainer
+	// cancel stops watching the events stream, it's set while there are
+	// subscriptions.
+	cancel context.CancelFunc
+}
+
+// NewContainerWatcher creates a watcher calling notify with the URI of
+// each updated resource.
+func NewContainerWatcher(notify func(uri string)) *ContainerWatcher {
+	return &ContainerWatcher{
+		notify:     notify,
+		containers: make(map[string]*watchedContainer),
+	}
+}
+
+// inspectState reads the state of the container, along with its full ID.
+func inspectState(ctx context.Context, container string) (string, containerState, error) {
+	outBytes, err := runDocker(ctx, "container", "inspect", "--format",
+		`{{json .Id}} {{json .State.Status}} {{if .State.Health}}{{json .State.Health.Status}}{{else}}""{{end}} {{.RestartCount}}`,
+		container)
+	if err != nil {
+		return "", containerState{}, err
+	}
+
+	var id string
+	state := containerState{Exists: true}
+	fields := strings.Fields(string(outBytes))
+	if len(fields) != 4 {
+		return "", containerState{}, fmt.Errorf("unexpected inspect output: %q", outBytes)
+	}
+	if err := json.Unmarshal([]byte(fields[0]), &id); err != nil {
+		return "", containerState{}, err
+	}
+	if err := json.Unmarshal([]byte(fields[1]), &state.Status); err != nil {
+		return "", containerState{}, err
+	}
+	if err := json.Unmarshal([]byte(fields[2]), &state.Health); err != nil {
+		return "", containerState{}, err
+	}
+	if err := json.Unmarshal([]byte(fields[3]), &state.RestartCount); err != nil {
+		return "", containerState{}, err
+	}
+	return id, state, nil
+}
+
+// subscribeParams are the params of resources/subscribe and unsubscribe.
+type subscribeParams struct {
+	URI string `json:"uri"`
+}
+
+// containerURI returns the container of the resource URI, which must be
+// the URI of a container resource.
+func containerURI(raw json.RawMessage) (string, string, error) {
+	var p subscribeParams
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return "", "", fmt.Errorf("%w: %w", params.ErrInvalidParams, err)
+	}
+	container, found := strings.CutPrefix(p.URI, ResourcePrefix+"containers/")
+	if !found || strings.Contains(container, "/") {
+		return "", "", fmt.Errorf("%w: only %scontainers/{id} resources can be subscribed to, got %q",
+			params.ErrInvalidParams, ResourcePrefix, p.URI)
+	}
+	if err := params.CheckFormat("uri", params.ContainerFormat, container); err != nil {
+		return "", "", err
+	}
+	return p.URI, container, nil
+}
+
+// Subscribe handles resources/subscribe requests, watching the container
+// of the resource.
+func (w *ContainerWatcher) Subscribe(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	uri, container, err := containerURI(raw)
+	if err != nil {
+		return nil, err
+	}
+	id, state, err := inspectState(ctx, container)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", uri, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	watched, exist := w.containers[id]
+	if !exist {
+		watched = &watchedContainer{uris: make(map[string]bool), state: state}
+		w.containers[id] = watched
+	}
+	watched.uris[uri] = true
+
+	if w.cancel == nil {
+		watchCtx, cancel := context.WithCancel(context.Background())
+		w.cancel = cancel
+		go w.watch(watchCtx)
+	}
+	return struct{}{}, nil
+}
+
+// Unsubscribe handles resources/unsubscribe requests, and stops watching
+// the events stream once there are no subscriptions left.
+func (w *ContainerWatcher) Unsubscribe(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	uri, _, err := containerURI(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, watched := range w.containers {
+		delete(watched.uris, uri)
+		if len(watched.uris) == 0 {
+			delete(w.containers, id)
+		}
+	}
+	if len(w.containers) == 0 && w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+	return struct{}{}, nil
+}
+
+// watch follows the container events until the context is cancelled,
+// reconnecting when the stream fails.
+func (w *ContainerWatcher) watch(ctx context.Context) {
+	for {
+		// Changes made while the stream wasn't watched are caught up on,
+		// and the stream replays the events from the start of the catch
+		// up, so the changes made in between aren't missed.
+		since := time.Now()
+		w.refreshAll(ctx)
+		if err := w.followEvents(ctx, since); err != nil && ctx.Err() == nil {
+			select {
+			case <-time.After(reconnectDelay):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// containerEvent is the part of a docker event needed to route it.
+type containerEvent struct {
+	Action string `json:"Action"`
+	Actor  struct {
+		ID string `json:"ID"`
+	} `json:"Actor"`
+}
+
+// followEvents reads the container events stream from the given time,
+// refreshing the state of the subscribed containers they concern.
+func (w *ContainerWatcher) followEvents(ctx context.Context, since time.Time) error {
+	// The timestamp is truncated to the second, replaying a few more events
+	// at worst, which don't notify unless the state changed.
+	eventsCmd := exec.CommandContext(ctx, "docker", "events",
+		"--since", strconv.FormatInt(since.Unix(), 10),
+		"--filter", "type=container", "--format", "{{json .}}")
+	stdout, err := eventsCmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := eventsCmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		var event containerEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		// exec_* events are frequent and don't change the state.
+		if strings.HasPrefix(event.Action, "exec_") {
+			continue
+		}
+		w.refresh(ctx, event.Actor.ID)
+	}
+	return eventsCmd.Wait()
+}
+
+// refreshAll refreshes the state of every subscribed container.
+func (w *ContainerWatcher) refreshAll(ctx context.Context) {
+	w.mu.Lock()
+	ids := make([]string, 0, len(w.containers))
+	for id := range w.containers {
+		ids = append(ids, id)
+	}
+	w.mu.Unlock()
+
+	for _, id := range ids {
+		w.refresh(ctx, id)
+	}
+}
+
+// refresh reads the state of the container, when subscribed, and notifies
+// the subscribers when it changed.
+func (w *ContainerWatcher) refresh(ctx context.Context, id string) {
+	w.mu.Lock()
+	_, exist := w.containers[id]
+	w.mu.Unlock()
+	if !exist {
+		return
+	}
+
+	_, state, err := inspectState(ctx, id)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		// The container was removed, which is a change too.
+		state = containerState{}
+	}
+
+	w.mu.Lock()
+	watched, exist := w.containers[id]
+	var uris []string
+	if exist && watched.state != state {
+		watched.state = state
+		for uri := range watched.uris {
+			uris = append(uris, uri)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, uri := range uris {
+		w.notify(uri)
+	}
+}
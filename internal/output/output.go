@@ -0,0 +1,262 @@
+// Package output keeps the results of the tools within a size clients can
+// handle: text outputs beyond the max response size are truncated, keeping
+// their head and tail, outputs can be stored as workspace files instead of
+// being inlined, and list outputs are paginated.
+package output
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultMaxSize is the default max size of the text of a tool result, in
+// bytes.
+const DefaultMaxSize = 128 * 1024
+
+// DefaultRetention is the default time the stored outputs are kept for.
+const DefaultRetention = 24 * time.Hour
+
+// storeDir is the workspace directory the outputs are stored in.
+const storeDir = "outputs"
+
+// Config holds the serve time configuration of the tool results.
+type Config struct {
+	// MaxSize is the max size of the text and of each embedded resource of
+	// a tool result, in bytes, zero disables it.
+	MaxSize int
+	// Retention is the time the stored outputs are kept for, they're
+	// removed when storing the next ones. Zero keeps them forever.
+	Retention time.Duration
+}
+
+// config is the configuration used by the middleware.
+var config = Config{MaxSize: DefaultMaxSize, Retention: DefaultRetention}
+
+// Configure sets the configuration used by the middleware. It's meant to be
+// called once, before the server starts.
+func Configure(cfg Config) {
+	config = cfg
+}
+
+// StoreOption is the tool option storing the full output as a workspace
+// file, returned as a resource URI instead of being inlined.
+func StoreOption() mcp.ToolOption {
+	return mcp.WithBoolean("store-output",
+		mcp.Description("Store the full output in the workspace and return its resource URI instead of the output"),
+		mcp.DefaultBool(false),
+	)
+}
+
+// sequence tells apart the outputs stored within the same nanosecond.
+var sequence atomic.Uint64
+
+// store writes the output of the tool to a new workspace file, with the
+// given extension, and returns its URI. The outputs past their retention
+// are removed first.
+func store(tool, ext, text string) (string, error) {
+	now := time.Now()
+	if config.Retention > 0 {
+		if err := workspace.RemoveOlder(storeDir, now.Add(-config.Retention)); err != nil {
+			return "", fmt.Errorf("failed to remove expired outputs: %w", err)
+		}
+	}
+	name := fmt.Sprintf("%s/%s-%d-%d%s", storeDir, tool, now.UnixNano(), sequence.Add(1), ext)
+	rel, err := workspace.WriteFile(name, []byte(text))
+	if err != nil {
+		return "", fmt.Errorf("failed to store output: %w", err)
+	}
+	return workspace.URI(rel), nil
+}
+
+// text returns the text of the text contents of the result, joined by new
+// lines, along with its size.
+func text(result *mcp.CallToolResult) (string, int) {
+	var texts []string
+	for _, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, textContent.Text)
+		}
+	}
+	joined := strings.Join(texts, "\n")
+	return joined, len(joined)
+}
+
+// truncate keeps the head and the tail of the text within size bytes,
+// cutting at line boundaries when there are some, and marks what's left
+// out with the marker.
+func truncate(text string, size int, marker func(omitted int) string) string {
+	if len(text) <= size {
+		return text
+	}
+	head := text[:size/2]
+	if i := strings.LastIndexByte(head, '\n'); i > 0 {
+		head = head[:i+1]
+	}
+	tail := text[len(text)-size/2:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		tail = tail[i+1:]
+	}
+	// The cuts must not split a multi-byte character.
+	for len(head) > 0 && !utf8.ValidString(head) {
+		head = head[:len(head)-1]
+	}
+	for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+		tail = tail[1:]
+	}
+	omitted := len(text) - len(head) - len(tail)
+	if head != "" && !strings.HasSuffix(head, "\n") {
+		head += "\n"
+	}
+	return head + marker(omitted) + tail
+}
+
+// storedResult replaces the text contents of the result with the URI of
+// the stored output, keeping its other contents.
+func storedResult(result *mcp.CallToolResult, uri string, size int) *mcp.CallToolResult {
+	contents := []mcp.Content{mcp.NewTextContent(fmt.Sprintf(
+		"The output, %d bytes, is stored as %s, read the resource to retrieve it", size, uri))}
+	for _, content := range result.Content {
+		if _, ok := content.(mcp.TextContent); !ok {
+			contents = append(contents, content)
+		}
+	}
+	result.Content = contents
+	return result
+}
+
+// limit truncates the text contents of the result, and replaces the
+// embedded resources larger than maxSize with their URI, so the result fits
+// in maxSize. The full text and the resources are stored first, unless
+// they're workspace files already, so the truncation markers and the notes
+// point to where they can be read.
+func limit(tool string, result *mcp.CallToolResult, maxSize int) *mcp.CallToolResult {
+	for i, content := range result.Content {
+		if resource, ok := content.(mcp.EmbeddedResource); ok {
+			result.Content[i] = limitResource(tool, resource, maxSize)
+		}
+	}
+
+	full, size := text(result)
+	if size <= maxSize {
+		return result
+	}
+	location := "it can't be stored"
+	if uri, err := store(tool, ".txt", full); err == nil {
+		location = "the full output is stored as " + uri
+	}
+	marker := func(omitted int) string {
+		return fmt.Sprintf("... [%d bytes truncated, %s] ...\n", omitted, location)
+	}
+
+	var indexes, sizes []int
+	for i, content := range result.Content {
+		if textContent, ok := content.(mcp.TextContent); ok {
+			indexes = append(indexes, i)
+			sizes = append(sizes, len(textContent.Text))
+		}
+	}
+	for j, share := range shares(sizes, maxSize) {
+		textContent := result.Content[indexes[j]].(mcp.TextContent)
+		textContent.Text = truncate(textContent.Text, share, marker)
+		result.Content[indexes[j]] = textContent
+	}
+	return result
+}
+
+// limitResource returns the embedded resource, or a note of where it can
+// be read when it's larger than maxSize.
+func limitResource(tool string, resource mcp.EmbeddedResource, maxSize int) mcp.Content {
+	var uri, data, ext string
+	switch contents := resource.Resource.(type) {
+	case mcp.TextResourceContents:
+		uri, data, ext = contents.URI, contents.Text, ".txt"
+	case mcp.BlobResourceContents:
+		// Blobs are stored as they're embedded, base64 encoded, since
+		// workspace files are read as text.
+		uri, data, ext = contents.URI, contents.Blob, ".base64"
+	default:
+		return resource
+	}
+	if len(data) <= maxSize {
+		return resource
+	}
+	if !strings.HasPrefix(uri, workspace.URIPrefix) {
+		stored, err := store(tool, ext, data)
+		if err != nil {
+			return mcp.NewTextContent(fmt.Sprintf(
+				"%s is too large to be embedded, %d bytes, and can't be stored: %v", uri, len(data), err))
+		}
+		uri = stored + " (" + uri + ")"
+	}
+	return mcp.NewTextContent(fmt.Sprintf(
+		"%s is too large to be embedded, %d bytes, read the resource to retrieve it", uri, len(data)))
+}
+
+// shares splits the max size between texts of the given sizes: the texts
+// smaller than an even share are kept whole, e.g. short notes, and what's
+// left is split evenly between the others.
+func shares(sizes []int, maxSize int) []int {
+	result := make([]int, len(sizes))
+	pending := make([]int, len(sizes))
+	for i := range sizes {
+		pending[i] = i
+	}
+	remaining := maxSize
+	for len(pending) > 0 {
+		even := remaining / len(pending)
+		var larger []int
+		for _, i := range pending {
+			if sizes[i] <= even {
+				result[i] = sizes[i]
+				remaining -= sizes[i]
+			} else {
+				larger = append(larger, i)
+			}
+		}
+		if len(larger) == len(pending) {
+			for _, i := range larger {
+				result[i] = even
+			}
+			break
+		}
+		pending = larger
+	}
+	return result
+}
+
+// Middleware stores the output of the tool calls asking for it, and keeps
+// the others within the max size of the configuration, unless it's 0.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		storeOutput, err := params.Bool(req, "store-output", false)
+		if err != nil {
+			return nil, err
+		}
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError {
+			return result, err
+		}
+
+		if storeOutput {
+			full, size := text(result)
+			uri, err := store(req.Params.Name, ".txt", full)
+			if err != nil {
+				return nil, err
+			}
+			result = storedResult(result, uri, size)
+		}
+		if config.MaxSize > 0 {
+			return limit(req.Params.Name, result, config.MaxSize), nil
+		}
+		return result, nil
+	}
+}
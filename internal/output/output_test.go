@@ -0,0 +1,213 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-docker/internal/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// configureWorkspace sets up a workspace in a new temporary directory for
+// the outputs to be stored in.
+func configureWorkspace(t *testing.T) {
+	t.Helper()
+	if err := workspace.Configure(t.TempDir()); err != nil {
+		t.Fatalf("failed to configure the workspace: %v", err)
+	}
+}
+
+// readStored returns the content of the stored output, given its URI.
+func readStored(t *testing.T, uri string) string {
+	t.Helper()
+	data, err := workspace.ReadFile(strings.TrimPrefix(uri, workspace.URIPrefix))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", uri, err)
+	}
+	return string(data)
+}
+
+// storedURI returns the workspace URI found in the text.
+func storedURI(t *testing.T, text string) string {
+	t.Helper()
+	i := strings.Index(text, workspace.URIPrefix)
+	if i < 0 {
+		t.Fatalf("no workspace URI in %q", text)
+	}
+	uri := text[i:]
+	if end := strings.IndexAny(uri, " ,]"); end >= 0 {
+		uri = uri[:end]
+	}
+	return uri
+}
+
+func TestTruncate(t *testing.T) {
+	marker := func(omitted int) string { return fmt.Sprintf("[%d]\n", omitted) }
+
+	tests := []struct {
+		name string
+		text string
+		size int
+		want string
+	}{
+		{"shorter", "abc", 4, "abc"},
+		{"exact size", "abcd", 4, "abcd"},
+		{"one byte over", "abcd", 3, "a\n[2]\nd"},
+		{"line boundaries", "1111\n2222\n3333\n4444\n", 12, "1111\n[10]\n4444\n"},
+		{"single line", strings.Repeat("x", 20), 10, "xxxxx\n[10]\nxxxxx"},
+		{"multi-byte characters", "ééééé", 6, "é\n[6]\né"},
+		{"zero size", "abc", 0, "[3]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.text, tt.size, marker)
+			if got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.text, tt.size, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncate(%q, %d) = %q, which isn't valid UTF-8", tt.text, tt.size, got)
+			}
+		})
+	}
+}
+
+func TestShares(t *testing.T) {
+	tests := []struct {
+		sizes   []int
+		maxSize int
+		want    []int
+	}{
+		{[]int{}, 100, []int{}},
+		{[]int{10, 20}, 100, []int{10, 20}},
+		{[]int{1000}, 100, []int{100}},
+		{[]int{10, 1000, 1000}, 110, []int{10, 50, 50}},
+		{[]int{30, 1000, 40}, 100, []int{30, 35, 35}},
+	}
+	for _, tt := range tests {
+		if got := shares(tt.sizes, tt.maxSize); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("shares(%v, %d) = %v, want %v", tt.sizes, tt.maxSize, got, tt.want)
+		}
+	}
+}
+
+func TestLimit(t *testing.T) {
+	configureWorkspace(t)
+
+	full := strings.Repeat("line\n", 100)
+	result := limit("test_tool", mcp.NewToolResultText(full), 50)
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.HasPrefix(text, "line\n") || !strings.HasSuffix(text, "line\n") {
+		t.Errorf("limit didn't keep the head and the tail of the text: %q", text)
+	}
+	if !strings.Contains(text, "bytes truncated, the full output is stored as "+workspace.URIPrefix+"outputs/test_tool-") {
+		t.Fatalf("limit didn't mark the truncation: %q", text)
+	}
+	if stored := readStored(t, storedURI(t, text)); stored != full {
+		t.Errorf("limit stored %d bytes, want the full %d bytes", len(stored), len(full))
+	}
+
+	short := limit("test_tool", mcp.NewToolResultText("short"), 50)
+	if text := short.Content[0].(mcp.TextContent).Text; text != "short" {
+		t.Errorf("limit changed the text within the max size: %q", text)
+	}
+}
+
+func TestLimitResource(t *testing.T) {
+	configureWorkspace(t)
+
+	small := mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: "docker://small", Text: "small"})
+	if got := limitResource("test_tool", small, 10); !reflect.DeepEqual(got, small) {
+		t.Errorf("limitResource changed the resource within the max size: %#v", got)
+	}
+
+	large := mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: "docker://large", Text: strings.Repeat("x", 20)})
+	note, ok := limitResource("test_tool", large, 10).(mcp.TextContent)
+	if !ok {
+		t.Fatalf("limitResource kept the resource larger than the max size")
+	}
+	if !strings.Contains(note.Text, "(docker://large) is too large to be embedded, 20 bytes") {
+		t.Errorf("unexpected note %q", note.Text)
+	}
+	uri := storedURI(t, note.Text)
+	if !strings.HasSuffix(uri, ".txt") || readStored(t, uri) != strings.Repeat("x", 20) {
+		t.Errorf("limitResource didn't store the text of the resource as %s", uri)
+	}
+
+	blob := mcp.NewEmbeddedResource(mcp.BlobResourceContents{URI: "docker://blob", Blob: "eHh4eHh4eHh4eHh4"})
+	note = limitResource("test_tool", blob, 10).(mcp.TextContent)
+	if uri := storedURI(t, note.Text); !strings.HasSuffix(uri, ".base64") || readStored(t, uri) != "eHh4eHh4eHh4eHh4" {
+		t.Errorf("limitResource didn't store the blob of the resource as %s", uri)
+	}
+
+	// Workspace files are pointed to as they are, without being stored again.
+	file := mcp.NewEmbeddedResource(mcp.TextResourceContents{URI: workspace.URIPrefix + "sbom.json", Text: strings.Repeat("x", 20)})
+	note = limitResource("test_tool", file, 10).(mcp.TextContent)
+	want := workspace.URIPrefix + "sbom.json is too large to be embedded, 20 bytes, read the resource to retrieve it"
+	if note.Text != want {
+		t.Errorf("limitResource = %q, want %q", note.Text, want)
+	}
+}
+
+func TestStoreRetention(t *testing.T) {
+	configureWorkspace(t)
+	saved := config
+	t.Cleanup(func() { config = saved })
+	Configure(Config{Retention: time.Hour})
+
+	expired, err := workspace.WriteFile("outputs/expired.txt", []byte("expired"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(workspace.Dir(), filepath.FromSlash(expired)), old, old); err != nil {
+		t.Fatal(err)
+	}
+	recent, err := workspace.WriteFile("outputs/recent.txt", []byte("recent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uri, err := store("test_tool", ".txt", "output")
+	if err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+	if readStored(t, uri) != "output" {
+		t.Errorf("store didn't write the output to %s", uri)
+	}
+	if _, err := workspace.ReadFile(expired); err == nil {
+		t.Errorf("store kept the output past its retention")
+	}
+	if _, err := workspace.ReadFile(recent); err != nil {
+		t.Errorf("store removed the output within its retention: %v", err)
+	}
+}
+
+func TestMiddlewareStoreOutput(t *testing.T) {
+	configureWorkspace(t)
+
+	handler := Middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("output"), nil
+	})
+	var req mcp.CallToolRequest
+	req.Params.Name = "test_tool"
+	req.Params.Arguments = map[string]interface{}{"store-output": true}
+
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handler failed: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.HasPrefix(text, "The output, 6 bytes, is stored as ") {
+		t.Errorf("unexpected result %q", text)
+	}
+	if stored := readStored(t, storedURI(t, text)); stored != "output" {
+		t.Errorf("stored output = %q, want %q", stored, "output")
+	}
+}
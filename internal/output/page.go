@@ -0,0 +1,115 @@
+package output
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// In this file, we define the cursor-based pagination of the list tools.
+// Cursors are opaque to the clients, and encode the offset of the next
+// page in the list.
+
+// DefaultPageSize is the default number of items of a page.
+const DefaultPageSize = 100
+
+// CursorOption is the tool option selecting the page to return.
+func CursorOption() mcp.ToolOption {
+	return mcp.WithString("cursor",
+		mcp.Description("The cursor of the page to return, as returned with the previous page"),
+	)
+}
+
+// PageSizeOption is the tool option setting the number of items of a page.
+func PageSizeOption() mcp.ToolOption {
+	return mcp.WithNumber("page-size",
+		mcp.Description("The number of items of a page"),
+		mcp.DefaultNumber(DefaultPageSize),
+		mcp.Min(1),
+	)
+}
+
+// Page is a page of a list.
+type Page struct {
+	Offset int
+	Size   int
+}
+
+// ParsePage reads the page selected by the cursor and page-size arguments.
+func ParsePage(req mcp.CallToolRequest) (Page, error) {
+	size, err := params.Int(req, "page-size", DefaultPageSize)
+	if err != nil {
+		return Page{}, err
+	}
+	if size < 1 {
+		return Page{}, fmt.Errorf("%w: argument %q must be at least 1, got %d", params.ErrInvalidParams, "page-size", size)
+	}
+	cursor, err := params.OptionalString(req, "cursor", "")
+	if err != nil {
+		return Page{}, err
+	}
+	page := Page{Size: size}
+	if cursor == "" {
+		return page, nil
+	}
+	offsetBytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		page.Offset, err = strconv.Atoi(string(offsetBytes))
+	}
+	if err != nil || page.Offset < 0 {
+		return Page{}, fmt.Errorf("%w: invalid cursor %q", params.ErrInvalidParams, cursor)
+	}
+	return page, nil
+}
+
+// cursor returns the cursor of the page at the offset.
+func cursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// Paginate returns the items of the page, along with the cursor of the
+// next page, which is empty on the last page.
+func Paginate[T any](items []T, page Page) ([]T, string) {
+	if page.Offset >= len(items) {
+		return items[:0], ""
+	}
+	end := page.Offset + page.Size
+	if end >= len(items) {
+		return items[page.Offset:], ""
+	}
+	return items[page.Offset:end], cursor(end)
+}
+
+// PaginateLines returns the lines of the page of the text output of a cli,
+// where each line is an item. When the output starts with a header, e.g.
+// the one of a table, it's repeated on every page.
+func PaginateLines(text string, page Page, header bool) (string, string) {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if text == "" {
+		lines = nil
+	}
+	var head []string
+	if header && len(lines) > 0 {
+		head, lines = lines[:1], lines[1:]
+	}
+	lines, next := Paginate(lines, page)
+	lines = append(append([]string(nil), head...), lines...)
+	if len(lines) == 0 {
+		return "", next
+	}
+	return strings.Join(lines, "\n") + "\n", next
+}
+
+// WithNextCursor appends the cursor of the next page to the result, when
+// there is one.
+func WithNextCursor(result *mcp.CallToolResult, next string) *mcp.CallToolResult {
+	if next != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+			"More results are available, pass cursor %q to get the next page", next)))
+	}
+	return result
+}
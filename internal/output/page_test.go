@@ -0,0 +1,126 @@
+package output
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func pageRequest(arguments map[string]interface{}) mcp.CallToolRequest {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = arguments
+	return req
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, offset := range []int{0, 1, 100, 123456} {
+		page, err := ParsePage(pageRequest(map[string]interface{}{"cursor": cursor(offset), "page-size": float64(10)}))
+		if err != nil {
+			t.Fatalf("ParsePage(cursor(%d)) failed: %v", offset, err)
+		}
+		if page != (Page{Offset: offset, Size: 10}) {
+			t.Errorf("ParsePage(cursor(%d)) = %+v", offset, page)
+		}
+	}
+
+	page, err := ParsePage(pageRequest(nil))
+	if err != nil || page != (Page{Size: DefaultPageSize}) {
+		t.Errorf("ParsePage() = %+v, %v, want the first page of the default size", page, err)
+	}
+}
+
+func TestParsePageInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+	}{
+		{"garbage cursor", map[string]interface{}{"cursor": "not a cursor!"}},
+		{"cursor not a number", map[string]interface{}{"cursor": base64.RawURLEncoding.EncodeToString([]byte("abc"))}},
+		{"negative cursor", map[string]interface{}{"cursor": base64.RawURLEncoding.EncodeToString([]byte("-5"))}},
+		{"padded cursor", map[string]interface{}{"cursor": base64.URLEncoding.EncodeToString([]byte("1"))}},
+		{"cursor of another type", map[string]interface{}{"cursor": float64(1)}},
+		{"zero page size", map[string]interface{}{"page-size": float64(0)}},
+		{"fractional page size", map[string]interface{}{"page-size": 1.5}},
+		{"page size not a number", map[string]interface{}{"page-size": "ten"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePage(pageRequest(tt.arguments)); !errors.Is(err, params.ErrInvalidParams) {
+				t.Errorf("ParsePage = %v, want an invalid params error", err)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{0, 1, 2, 3, 4}
+	tests := []struct {
+		page Page
+		want []int
+		next string
+	}{
+		{Page{Offset: 0, Size: 2}, []int{0, 1}, cursor(2)},
+		{Page{Offset: 2, Size: 2}, []int{2, 3}, cursor(4)},
+		{Page{Offset: 4, Size: 2}, []int{4}, ""},
+		{Page{Offset: 3, Size: 2}, []int{3, 4}, ""},
+		{Page{Offset: 0, Size: 100}, items, ""},
+		{Page{Offset: 5, Size: 2}, []int{}, ""},
+		{Page{Offset: 1000, Size: 2}, []int{}, ""},
+	}
+	for _, tt := range tests {
+		got, next := Paginate(items, tt.page)
+		if !reflect.DeepEqual(got, tt.want) || next != tt.next {
+			t.Errorf("Paginate(%+v) = %v, %q, want %v, %q", tt.page, got, next, tt.want, tt.next)
+		}
+	}
+
+	// Following the cursors returns every item once.
+	var all []int
+	page := Page{Size: 2}
+	for {
+		got, next := Paginate(items, page)
+		all = append(all, got...)
+		if next == "" {
+			break
+		}
+		var err error
+		page, err = ParsePage(pageRequest(map[string]interface{}{"cursor": next, "page-size": float64(2)}))
+		if err != nil {
+			t.Fatalf("ParsePage(%q) failed: %v", next, err)
+		}
+	}
+	if !reflect.DeepEqual(all, items) {
+		t.Errorf("the pages hold %v, want %v", all, items)
+	}
+}
+
+func TestPaginateLines(t *testing.T) {
+	table := "NAME\na\nb\nc\n"
+	tests := []struct {
+		name   string
+		text   string
+		page   Page
+		header bool
+		want   string
+		next   string
+	}{
+		{"first page", table, Page{Offset: 0, Size: 2}, true, "NAME\na\nb\n", cursor(2)},
+		{"last page repeats the header", table, Page{Offset: 2, Size: 2}, true, "NAME\nc\n", ""},
+		{"past the end", table, Page{Offset: 10, Size: 2}, true, "NAME\n", ""},
+		{"without header", table, Page{Offset: 0, Size: 2}, false, "NAME\na\n", cursor(2)},
+		{"without trailing new line", "a\nb", Page{Offset: 1, Size: 2}, false, "b\n", ""},
+		{"empty", "", Page{Offset: 0, Size: 2}, true, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next := PaginateLines(tt.text, tt.page, tt.header)
+			if got != tt.want || next != tt.next {
+				t.Errorf("PaginateLines = %q, %q, want %q, %q", got, next, tt.want, tt.next)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package params
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// In this file, we define the helpers used by the tool handlers to read
+// their arguments. Instead of panicking on a failed type assertion, every
+// helper returns a descriptive error naming the offending argument.
+
+// String returns the value of the required string argument key.
+func String(req mcp.CallToolRequest, key string) (string, error) {
+	value, exist := req.Params.Arguments[key]
+	if !exist || value == nil {
+		return "", fmt.Errorf("%w: missing required argument %q", ErrInvalidParams, key)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: argument %q must be a string, got %T", ErrInvalidParams, key, value)
+	}
+	if str == "" {
+		return "", fmt.Errorf("%w: argument %q must not be empty", ErrInvalidParams, key)
+	}
+	return str, nil
+}
+
+// OptionalString returns the value of the string argument key, or def
+// when the argument is not provided.
+func OptionalString(req mcp.CallToolRequest, key string, def string) (string, error) {
+	value, exist := req.Params.Arguments[key]
+	if !exist || value == nil {
+		return def, nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: argument %q must be a string, got %T", ErrInvalidParams, key, value)
+	}
+	return str, nil
+}
+
+// Bool returns the value of the boolean argument key, or def when the
+// argument is not provided. String values such as "true" or "false" are
+// accepted as well, for clients that do not send typed JSON values.
+func Bool(req mcp.CallToolRequest, key string, def bool) (bool, error) {
+	value, exist := req.Params.Arguments[key]
+	if !exist || value == nil {
+		return def, nil
+	}
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		if v == "" {
+			return def, nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("%w: argument %q must be a boolean, got %q", ErrInvalidParams, key, v)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("%w: argument %q must be a boolean, got %T", ErrInvalidParams, key, value)
+	}
+}
+
+// Int returns the value of the numeric argument key, or def when the
+// argument is not provided. The value must be a whole number.
+func Int(req mcp.CallToolRequest, key string, def int) (int, error) {
+	value, exist := req.Params.Arguments[key]
+	if !exist || value == nil {
+		return def, nil
+	}
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("%w: argument %q must be a whole number, got %v", ErrInvalidParams, key, v)
+		}
+		return int(v), nil
+	case int:
+		return v, nil
+	case string:
+		if v == "" {
+			return def, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("%w: argument %q must be a number, got %q", ErrInvalidParams, key, v)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("%w: argument %q must be a number, got %T", ErrInvalidParams, key, value)
+	}
+}
+
+// StringSlice returns the values of the array argument key, or nil when
+// the argument is not provided. A single string is treated as an array
+// with one element.
+func StringSlice(req mcp.CallToolRequest, key string) ([]string, error) {
+	value, exist := req.Params.Arguments[key]
+	if !exist || value == nil {
+		return nil, nil
+	}
+	switch v := value.(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for i, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: argument %q must be an array of strings, element %d is %T", ErrInvalidParams, key, i, item)
+			}
+			values = append(values, str)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("%w: argument %q must be an array of strings, got %T", ErrInvalidParams, key, value)
+	}
+}
+
+// StringArray is the schema of an array property whose items are strings.
+func StringArray() mcp.PropertyOption {
+	return mcp.Items(map[string]interface{}{"type": "string"})
+}
@@ -0,0 +1,211 @@
+package params
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the validation layer that checks the arguments
+// of a tool call against the tool's input schema before the handler runs,
+// so malformed requests are rejected with a clear invalid params error.
+
+// ErrInvalidParams is wrapped by every error caused by malformed arguments.
+var ErrInvalidParams = errors.New("invalid params")
+
+const (
+	// ContainerFormat marks a string property holding a container ID or name.
+	ContainerFormat = "docker-container"
+	// ImageFormat marks a string property holding an image reference or ID.
+	ImageFormat = "docker-image"
+	// NameFormat marks a string property holding the ID or name of another
+	// docker object, e.g. a volume or a network.
+	NameFormat = "docker-name"
+)
+
+var (
+	containerRegexp = regexp.MustCompile(`^/?[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	imageRegexp     = regexp.MustCompile(imageReferencePattern())
+)
+
+// imageReferencePattern builds the image reference grammar used by the
+// docker cli: [domain[:port]/]path[/path...][:tag][@digest].
+func imageReferencePattern() string {
+	alphanumeric := `[a-z0-9]+`
+	separator := `(?:[._]|__|[-]+)`
+	pathComponent := alphanumeric + `(?:` + separator + alphanumeric + `)*`
+	domainComponent := `(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])`
+	domain := `(?:` + domainComponent + `(?:\.` + domainComponent + `)*|\[[a-fA-F0-9:]+\])(?::[0-9]+)?`
+	name := `(?:` + domain + `/)?` + pathComponent + `(?:/` + pathComponent + `)*`
+	tag := `[\w][\w.-]{0,127}`
+	digest := `[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}`
+	return `^` + name + `(?::` + tag + `)?(?:@` + digest + `)?$`
+}
+
+// Format sets the format of a string property, one of ContainerFormat or
+// ImageFormat, which is then enforced by Validate.
+func Format(format string) mcp.PropertyOption {
+	return func(schema map[string]interface{}) {
+		schema["format"] = format
+	}
+}
+
+// Validate wraps the handler of the given tool, so the call arguments are
+// checked against the tool's input schema before the handler is invoked.
+func Validate(tool mcp.Tool, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := validateArguments(tool.InputSchema, req.Params.Arguments); err != nil {
+			return nil, fmt.Errorf("%s: %w", tool.Name, err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func validateArguments(schema mcp.ToolInputSchema, arguments map[string]interface{}) error {
+	for _, name := range schema.Required {
+		value, exist := arguments[name]
+		if !exist || value == nil {
+			return fmt.Errorf("%w: missing required argument %q", ErrInvalidParams, name)
+		}
+		if str, ok := value.(string); ok && strings.TrimSpace(str) == "" {
+			return fmt.Errorf("%w: argument %q must not be empty", ErrInvalidParams, name)
+		}
+	}
+
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := arguments[name]
+		if value == nil {
+			continue
+		}
+		property, ok := schema.Properties[name].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%w: unknown argument %q", ErrInvalidParams, name)
+		}
+		if err := validateValue(name, property, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateValue(name string, property map[string]interface{}, value interface{}) error {
+	switch property["type"] {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%w: argument %q must be a string, got %T", ErrInvalidParams, name, value)
+		}
+		return validateString(name, property, str)
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+		case string:
+			if _, err := strconv.ParseBool(v); err != nil && v != "" {
+				return fmt.Errorf("%w: argument %q must be a boolean, got %q", ErrInvalidParams, name, v)
+			}
+		default:
+			return fmt.Errorf("%w: argument %q must be a boolean, got %T", ErrInvalidParams, name, value)
+		}
+	case "number":
+		var number float64
+		switch v := value.(type) {
+		case float64:
+			number = v
+		case string:
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("%w: argument %q must be a number, got %q", ErrInvalidParams, name, v)
+			}
+			number = n
+		default:
+			return fmt.Errorf("%w: argument %q must be a number, got %T", ErrInvalidParams, name, value)
+		}
+		if min, ok := property["minimum"].(float64); ok && number < min {
+			return fmt.Errorf("%w: argument %q must be at least %v, got %v", ErrInvalidParams, name, min, number)
+		}
+		if max, ok := property["maximum"].(float64); ok && number > max {
+			return fmt.Errorf("%w: argument %q must be at most %v, got %v", ErrInvalidParams, name, max, number)
+		}
+	case "array":
+		var items []interface{}
+		switch v := value.(type) {
+		case []interface{}:
+			items = v
+		case string:
+			// A single string is accepted as an array with one element.
+			items = []interface{}{v}
+		default:
+			return fmt.Errorf("%w: argument %q must be an array, got %T", ErrInvalidParams, name, value)
+		}
+		itemSchema, ok := property["items"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			if err := validateValue(fmt.Sprintf("%s[%d]", name, i), itemSchema, item); err != nil {
+				return err
+			}
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fmt.Errorf("%w: argument %q must be an object, got %T", ErrInvalidParams, name, value)
+		}
+	}
+	return nil
+}
+
+func validateString(name string, property map[string]interface{}, value string) error {
+	if value == "" {
+		return nil
+	}
+	if enum, ok := property["enum"].([]string); ok {
+		found := false
+		for _, allowed := range enum {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: argument %q must be one of [%s], got %q",
+				ErrInvalidParams, name, strings.Join(enum, ", "), value)
+		}
+	}
+	switch property["format"] {
+	case ContainerFormat:
+		if !containerRegexp.MatchString(value) {
+			return fmt.Errorf("%w: argument %q is not a valid container ID or name: %q", ErrInvalidParams, name, value)
+		}
+	case ImageFormat:
+		if !imageRegexp.MatchString(value) {
+			return fmt.Errorf("%w: argument %q is not a valid image reference: %q", ErrInvalidParams, name, value)
+		}
+	case NameFormat:
+		if !containerRegexp.MatchString(value) {
+			return fmt.Errorf("%w: argument %q is not a valid ID or name: %q", ErrInvalidParams, name, value)
+		}
+	}
+	return nil
+}
+
+// CheckFormat checks the value against the format, for values which don't
+// come from tool arguments, e.g. resource URI variables.
+func CheckFormat(name, format, value string) error {
+	if value == "" {
+		return fmt.Errorf("%w: argument %q is required", ErrInvalidParams, name)
+	}
+	return validateString(name, map[string]interface{}{"format": format}, value)
+}
@@ -0,0 +1,147 @@
+package params
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var testTool = mcp.NewTool("test_tool",
+	mcp.WithString("container",
+		mcp.Required(),
+		Format(ContainerFormat),
+	),
+	mcp.WithString("image",
+		Format(ImageFormat),
+	),
+	mcp.WithString("volume",
+		Format(NameFormat),
+	),
+	mcp.WithString("format",
+		mcp.Enum("table", "json"),
+	),
+	mcp.WithNumber("limit",
+		mcp.Min(1),
+		mcp.Max(100),
+	),
+	mcp.WithBoolean("all"),
+	mcp.WithArray("severity",
+		mcp.Items(map[string]interface{}{
+			"type": "string",
+			"enum": []string{"LOW", "HIGH"},
+		}),
+	),
+	mcp.WithArray("change",
+		StringArray(),
+	),
+	mcp.WithObject("labels"),
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		valid     bool
+	}{
+		{"required only", map[string]interface{}{"container": "web"}, true},
+		{"missing required", map[string]interface{}{"image": "alpine"}, false},
+		{"null required", map[string]interface{}{"container": nil}, false},
+		{"blank required", map[string]interface{}{"container": "  "}, false},
+		{"unknown argument", map[string]interface{}{"container": "web", "force": true}, false},
+		{"null optional", map[string]interface{}{"container": "web", "image": nil}, true},
+
+		{"enum", map[string]interface{}{"container": "web", "format": "json"}, true},
+		{"enum mismatch", map[string]interface{}{"container": "web", "format": "yaml"}, false},
+		{"array enum", map[string]interface{}{"container": "web", "severity": []interface{}{"LOW", "HIGH"}}, true},
+		{"array enum mismatch", map[string]interface{}{"container": "web", "severity": []interface{}{"LOW", "MEDIUM"}}, false},
+
+		{"min", map[string]interface{}{"container": "web", "limit": float64(1)}, true},
+		{"max", map[string]interface{}{"container": "web", "limit": float64(100)}, true},
+		{"below min", map[string]interface{}{"container": "web", "limit": float64(0)}, false},
+		{"above max", map[string]interface{}{"container": "web", "limit": float64(101)}, false},
+
+		{"container ID", map[string]interface{}{"container": "3f2a9c1b7d4e"}, true},
+		{"container name with slash", map[string]interface{}{"container": "/web_1.a-b"}, true},
+		{"container flag", map[string]interface{}{"container": "--privileged"}, false},
+		{"container dash", map[string]interface{}{"container": "-web"}, false},
+		{"container space", map[string]interface{}{"container": "web db"}, false},
+		{"image", map[string]interface{}{"container": "web", "image": "alpine"}, true},
+		{"image with registry and tag", map[string]interface{}{"container": "web", "image": "registry.local:5000/team/app:1.2.3"}, true},
+		{"image with digest", map[string]interface{}{"container": "web", "image": "alpine@sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"}, true},
+		{"image flag", map[string]interface{}{"container": "web", "image": "--output=/etc"}, false},
+		{"image dash", map[string]interface{}{"container": "web", "image": "-alpine"}, false},
+		{"image upper case", map[string]interface{}{"container": "web", "image": "Alpine"}, false},
+		{"name", map[string]interface{}{"container": "web", "volume": "data_1"}, true},
+		{"name flag", map[string]interface{}{"container": "web", "volume": "-v"}, false},
+
+		{"bool", map[string]interface{}{"container": "web", "all": true}, true},
+		{"bool string", map[string]interface{}{"container": "web", "all": "true"}, true},
+		{"bool empty string", map[string]interface{}{"container": "web", "all": ""}, true},
+		{"bool bad string", map[string]interface{}{"container": "web", "all": "yes please"}, false},
+		{"bool number", map[string]interface{}{"container": "web", "all": float64(1)}, false},
+		{"number string", map[string]interface{}{"container": "web", "limit": "10"}, true},
+		{"number string below min", map[string]interface{}{"container": "web", "limit": "0"}, false},
+		{"number bad string", map[string]interface{}{"container": "web", "limit": "ten"}, false},
+		{"number bool", map[string]interface{}{"container": "web", "limit": true}, false},
+		{"array string", map[string]interface{}{"container": "web", "change": "ENV A=1"}, true},
+		{"array string enum mismatch", map[string]interface{}{"container": "web", "severity": "MEDIUM"}, false},
+		{"array number item", map[string]interface{}{"container": "web", "change": []interface{}{float64(1)}}, false},
+		{"array number", map[string]interface{}{"container": "web", "change": float64(1)}, false},
+		{"string number", map[string]interface{}{"container": float64(1)}, false},
+		{"object", map[string]interface{}{"container": "web", "labels": map[string]interface{}{"a": "b"}}, true},
+		{"object string", map[string]interface{}{"container": "web", "labels": "a=b"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := Validate(testTool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				return mcp.NewToolResultText("ok"), nil
+			})
+			var req mcp.CallToolRequest
+			req.Params.Name = testTool.Name
+			req.Params.Arguments = tt.arguments
+
+			_, err := handler(context.Background(), req)
+			if tt.valid {
+				if err != nil || !called {
+					t.Errorf("Validate rejected valid arguments: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("Validate = %v, want an invalid params error", err)
+			}
+			if called {
+				t.Errorf("handler called with invalid arguments")
+			}
+		})
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		format, value string
+		valid         bool
+	}{
+		{ContainerFormat, "web", true},
+		{ContainerFormat, "", false},
+		{ContainerFormat, "-web", false},
+		{ImageFormat, "nginx:1.25-alpine", true},
+		{ImageFormat, "nginx:-1", false},
+		{NameFormat, "bridge", true},
+		{NameFormat, "../etc", false},
+	}
+	for _, tt := range tests {
+		err := CheckFormat("value", tt.format, tt.value)
+		if tt.valid != (err == nil) {
+			t.Errorf("CheckFormat(%q, %q) = %v, want valid %t", tt.format, tt.value, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("CheckFormat(%q, %q) = %v, want an invalid params error", tt.format, tt.value, err)
+		}
+	}
+}
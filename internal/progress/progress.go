@@ -0,0 +1,121 @@
+// Package progress reports the progress of long-running tool calls, e.g.
+// pulls and scans, as MCP progress notifications, when the client asked
+// for them by sending a progress token along with the request.
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// minInterval is the minimum interval between two notifications carrying
+// the same message, so fast moving progress, e.g. layer downloads, doesn't
+// flood the client.
+const minInterval = 250 * time.Millisecond
+
+// Reporter sends the progress notifications of a tool call. A nil Reporter
+// is valid and reports nothing, which is what tool calls without progress
+// token get.
+type Reporter struct {
+	ctx    context.Context
+	server *server.MCPServer
+	token  mcp.ProgressToken
+
+	mu       sync.Mutex
+	progress float64
+	total    float64
+	message  string
+	sent     time.Time
+}
+
+// NewReporter returns the reporter of the tool call, or nil when the client
+// didn't send a progress token.
+func NewReporter(ctx context.Context, req mcp.CallToolRequest) *Reporter {
+	if req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return nil
+	}
+	return &Reporter{ctx: ctx, server: srv, token: req.Params.Meta.ProgressToken}
+}
+
+// Report sends the progress, out of total when it's known, i.e. not 0. As
+// the progress must increase with each notification, a progress lower than
+// or equal to the last one reported is dropped, except for the first one,
+// which may be 0 to start a percentage from scratch.
+func (r *Reporter) Report(progress, total float64, message string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	now := time.Now()
+	if (progress <= r.progress && !r.sent.IsZero()) ||
+		(message == r.message && now.Sub(r.sent) < minInterval && (total == 0 || progress < total)) {
+		r.mu.Unlock()
+		return
+	}
+	r.progress, r.total, r.message, r.sent = progress, total, message, now
+	r.mu.Unlock()
+
+	notification := map[string]interface{}{
+		"progressToken": r.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		notification["total"] = total
+	}
+	if message != "" {
+		notification["message"] = message
+	}
+	// Progress is best effort: a notification which can't be queued, e.g.
+	// because the client is slow to read them, is dropped.
+	_ = r.server.SendNotificationToClient(r.ctx, "notifications/progress", notification)
+}
+
+// Stage reports the start of a new stage of the call, e.g. the download of
+// the vulnerability DB, when its progress can't be measured otherwise. It
+// steps the progress on the scale reported so far: by one stage without a
+// total, and by one unit, e.g. a percent, within the total otherwise.
+func (r *Reporter) Stage(message string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	progress, total := r.progress+1, r.total
+	if total > 0 && progress >= total {
+		progress = (r.progress + total) / 2
+	}
+	r.mu.Unlock()
+	r.Report(progress, total, message)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the reporter.
+func NewContext(ctx context.Context, r *Reporter) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the reporter carried by the context, or nil.
+func FromContext(ctx context.Context) *Reporter {
+	r, _ := ctx.Value(contextKey{}).(*Reporter)
+	return r
+}
+
+// Middleware carries the reporter of each tool call in its context, so the
+// helpers shared by the tools, e.g. trivy scans, report their progress
+// without it being passed around.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if r := NewReporter(ctx, req); r != nil {
+			ctx = NewContext(ctx, r)
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,131 @@
+package sbom
+
+import (
+	"sort"
+	"strings"
+)
+
+// In this file, we define the comparison of two SBOMs. Packages are
+// matched by ecosystem and name, so a package whose version changed, e.g.
+// on a base image upgrade, is reported as changed rather than as removed
+// and added.
+
+// PackageChange is a package whose versions differ between two SBOMs.
+type PackageChange struct {
+	Name        string `json:"name"`
+	Ecosystem   string `json:"ecosystem"`
+	FromVersion string `json:"fromVersion"`
+	ToVersion   string `json:"toVersion"`
+	// Direction is upgrade, downgrade or changed, when the versions
+	// can't be ordered, e.g. when several versions are installed.
+	Direction string `json:"direction"`
+}
+
+// Diff is the difference between the packages of two SBOMs.
+type Diff struct {
+	Added     []Package       `json:"added"`
+	Removed   []Package       `json:"removed"`
+	Changed   []PackageChange `json:"changed"`
+	Unchanged int             `json:"unchanged"`
+}
+
+type packageKey struct {
+	ecosystem string
+	name      string
+}
+
+// packageVersions groups the packages by key, along with their sorted
+// versions, as a package may be installed in several versions.
+func packageVersions(doc *Document) (map[packageKey][]Package, map[packageKey]string) {
+	packages := make(map[packageKey][]Package)
+	for _, pkg := range doc.Packages {
+		key := packageKey{ecosystem: pkg.Ecosystem, name: pkg.Name}
+		packages[key] = append(packages[key], pkg)
+	}
+
+	versions := make(map[packageKey]string, len(packages))
+	for key, pkgs := range packages {
+		list := make([]string, 0, len(pkgs))
+		seen := make(map[string]bool)
+		for _, pkg := range pkgs {
+			if !seen[pkg.Version] {
+				seen[pkg.Version] = true
+				list = append(list, pkg.Version)
+			}
+		}
+		sort.Slice(list, func(i, j int) bool { return CompareVersions(list[i], list[j]) < 0 })
+		versions[key] = strings.Join(list, ", ")
+	}
+	return packages, versions
+}
+
+// DiffDocuments compares the packages of the base and target SBOMs.
+func DiffDocuments(base, target *Document) Diff {
+	diff := Diff{
+		Added:   []Package{},
+		Removed: []Package{},
+		Changed: []PackageChange{},
+	}
+	basePackages, baseVersions := packageVersions(base)
+	targetPackages, targetVersions := packageVersions(target)
+
+	for key, pkgs := range targetPackages {
+		fromVersion, exist := baseVersions[key]
+		if !exist {
+			diff.Added = append(diff.Added, pkgs...)
+			continue
+		}
+		toVersion := targetVersions[key]
+		if fromVersion == toVersion {
+			diff.Unchanged++
+			continue
+		}
+		change := PackageChange{
+			Name:        key.name,
+			Ecosystem:   key.ecosystem,
+			FromVersion: fromVersion,
+			ToVersion:   toVersion,
+			Direction:   "changed",
+		}
+		if len(basePackages[key]) == 1 && len(pkgs) == 1 {
+			// Versions which differ but compare equal, e.g. v1.2 and 1.2,
+			// are left as changed.
+			switch order := CompareVersions(fromVersion, toVersion); {
+			case order < 0:
+				change.Direction = "upgrade"
+			case order > 0:
+				change.Direction = "downgrade"
+			}
+		}
+		diff.Changed = append(diff.Changed, change)
+	}
+	for key, pkgs := range basePackages {
+		if _, exist := targetVersions[key]; !exist {
+			diff.Removed = append(diff.Removed, pkgs...)
+		}
+	}
+
+	sortPackages(diff.Added)
+	sortPackages(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		if diff.Changed[i].Ecosystem != diff.Changed[j].Ecosystem {
+			return diff.Changed[i].Ecosystem < diff.Changed[j].Ecosystem
+		}
+		return diff.Changed[i].Name < diff.Changed[j].Name
+	})
+	return diff
+}
+
+// sortPackages sorts the packages by ecosystem, name and version.
+func sortPackages(packages []Package) {
+	sort.Slice(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return CompareVersions(a.Version, b.Version) < 0
+	})
+}
@@ -0,0 +1,57 @@
+package sbom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffDocuments(t *testing.T) {
+	base := &Document{Packages: []Package{
+		{Name: "openssl", Version: "3.0.2", Ecosystem: "deb"},
+		{Name: "zlib", Version: "1.2.13", Ecosystem: "deb"},
+		{Name: "curl", Version: "8.5.0", Ecosystem: "deb"},
+		{Name: "left-pad", Version: "v1.3.0", Ecosystem: "npm"},
+		{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"},
+		{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+		{Name: "requests", Version: "2.31.0", Ecosystem: "pypi"},
+		{Name: "bash", Version: "5.2", Ecosystem: "deb"},
+	}}
+	target := &Document{Packages: []Package{
+		{Name: "openssl", Version: "3.0.13", Ecosystem: "deb"},
+		{Name: "zlib", Version: "1.2.11", Ecosystem: "deb"},
+		{Name: "curl", Version: "8.5.0", Ecosystem: "deb"},
+		{Name: "left-pad", Version: "1.3.0", Ecosystem: "npm"},
+		{Name: "lodash", Version: "4.17.21", Ecosystem: "npm"},
+		{Name: "requests", Version: "2.31.0", Ecosystem: "npm"},
+		{Name: "bash", Version: "5.2", Ecosystem: "deb"},
+		{Name: "bash", Version: "5.2", Ecosystem: "deb"},
+	}}
+
+	want := Diff{
+		Added: []Package{
+			{Name: "requests", Version: "2.31.0", Ecosystem: "npm"},
+		},
+		Removed: []Package{
+			{Name: "requests", Version: "2.31.0", Ecosystem: "pypi"},
+		},
+		Changed: []PackageChange{
+			{Name: "openssl", Ecosystem: "deb", FromVersion: "3.0.2", ToVersion: "3.0.13", Direction: "upgrade"},
+			{Name: "zlib", Ecosystem: "deb", FromVersion: "1.2.13", ToVersion: "1.2.11", Direction: "downgrade"},
+			{Name: "left-pad", Ecosystem: "npm", FromVersion: "v1.3.0", ToVersion: "1.3.0", Direction: "changed"},
+			{Name: "lodash", Ecosystem: "npm", FromVersion: "4.17.20, 4.17.21", ToVersion: "4.17.21", Direction: "changed"},
+		},
+		Unchanged: 2,
+	}
+	got := DiffDocuments(base, target)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffDocuments =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDiffDocumentsEmpty(t *testing.T) {
+	got := DiffDocuments(&Document{}, &Document{})
+	want := Diff{Added: []Package{}, Removed: []Package{}, Changed: []PackageChange{}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffDocuments = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,378 @@
+package sbom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// In this file, we define the classification of licenses into permissive,
+// copyleft and unknown ones, and the license report built from it. SPDX
+// expressions are evaluated as alternatives of licenses which all apply,
+// e.g. "MIT OR GPL-2.0" is permissive, as the MIT license can be chosen.
+
+// License categories.
+const (
+	CategoryPermissive = "permissive"
+	CategoryCopyleft   = "copyleft"
+	CategoryUnknown    = "unknown"
+)
+
+// copyleftPrefixes and permissivePrefixes identify the licenses by the
+// prefix of their upper cased SPDX ID or common name, e.g. GPL-2.0+ or
+// LGPL-2.1-only.
+var (
+	copyleftPrefixes = []string{
+		"GPL", "LGPL", "AGPL", "MPL", "EPL", "CDDL", "EUPL", "OSL", "CPL",
+		"CC-BY-SA", "GFDL", "SSPL", "SLEEPYCAT", "QPL", "RPL", "APSL",
+	}
+	permissivePrefixes = []string{
+		"MIT", "EXPAT", "APACHE", "BSD", "0BSD", "ISC", "ZLIB", "UNLICENSE",
+		"CC0", "PSF", "PYTHON", "BSL-1.0", "BOOST", "X11", "POSTGRESQL",
+		"OPENSSL", "CURL", "WTFPL", "PUBLIC-DOMAIN", "ARTISTIC", "ZPL",
+		"NCSA", "MS-PL", "UPL", "HPND", "W3C", "RUBY", "UNICODE", "BLUEOAK",
+	}
+)
+
+// alternatives turns the license expression into its alternatives, each
+// one listing licenses which all apply, e.g. "(MIT OR BSD-3-Clause) AND
+// Zlib" has the alternatives MIT and Zlib, and BSD-3-Clause and Zlib.
+// Operators are matched ignoring case, as they're lower cased in non SPDX
+// license fields, and exceptions are ignored.
+func alternatives(expr string) [][]string {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr))
+	p := &expressionParser{tokens: tokens}
+	var alts [][]string
+	for p.pos < len(p.tokens) {
+		// Unbalanced closing parentheses are skipped.
+		alts = append(alts, p.or()...)
+		p.pos++
+	}
+	return alts
+}
+
+// expressionParser parses license expressions into alternatives.
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *expressionParser) or() [][]string {
+	alts := p.and()
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		alts = append(alts, p.and()...)
+	}
+	return alts
+}
+
+func (p *expressionParser) and() [][]string {
+	alts := p.atom()
+	for strings.EqualFold(p.peek(), "AND") {
+		p.pos++
+		right := p.atom()
+		var product [][]string
+		for _, l := range alts {
+			for _, r := range right {
+				product = append(product, append(append([]string{}, l...), r...))
+			}
+		}
+		// An empty operand, e.g. in "MIT AND", doesn't drop the other one.
+		if len(alts) == 0 || len(right) == 0 {
+			product = append(alts, right...)
+		}
+		alts = product
+	}
+	return alts
+}
+
+func (p *expressionParser) atom() [][]string {
+	if p.peek() == "(" {
+		p.pos++
+		alts := p.or()
+		if p.peek() == ")" {
+			p.pos++
+		}
+		return alts
+	}
+
+	// Licenses may be names made of several words, e.g. Apache License 2.0.
+	var words []string
+	for token := p.peek(); token != "" && token != "(" && token != ")" &&
+		!strings.EqualFold(token, "AND") && !strings.EqualFold(token, "OR"); token = p.peek() {
+		p.pos++
+		if strings.EqualFold(token, "WITH") {
+			// The exception is the next word.
+			p.pos++
+			continue
+		}
+		words = append(words, token)
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	return [][]string{{strings.Join(words, " ")}}
+}
+
+// classifyLicense classifies a single license ID or name.
+func classifyLicense(license string) string {
+	upper := strings.ToUpper(strings.TrimSpace(license))
+	upper = strings.ReplaceAll(upper, " ", "-")
+	for _, prefix := range copyleftPrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return CategoryCopyleft
+		}
+	}
+	for _, prefix := range permissivePrefixes {
+		if strings.HasPrefix(upper, prefix) {
+			return CategoryPermissive
+		}
+	}
+	return CategoryUnknown
+}
+
+// ClassifyLicense classifies a license expression. An expression is
+// permissive when one of its alternatives only holds permissive licenses,
+// copyleft when every alternative holds a copyleft license, and unknown
+// otherwise, including when it's empty.
+func ClassifyLicense(expr string) string {
+	alts := alternatives(expr)
+	if len(alts) == 0 {
+		return CategoryUnknown
+	}
+	copyleft := 0
+	for _, alt := range alts {
+		permissive := true
+		hasCopyleft := false
+		for _, license := range alt {
+			switch classifyLicense(license) {
+			case CategoryCopyleft:
+				hasCopyleft = true
+				permissive = false
+			case CategoryUnknown:
+				permissive = false
+			}
+		}
+		if permissive {
+			return CategoryPermissive
+		}
+		if hasCopyleft {
+			copyleft++
+		}
+	}
+	if copyleft == len(alts) {
+		return CategoryCopyleft
+	}
+	return CategoryUnknown
+}
+
+// Denylist lists the licenses which must not ship. Entries are license ID
+// prefixes, e.g. AGPL or GPL-3.0, or categories, e.g. copyleft, matched
+// ignoring case.
+type Denylist []string
+
+// ParseDenylist returns the denylist of the entries, which must not be
+// empty, as an empty prefix would deny every license.
+func ParseDenylist(entries []string) (Denylist, error) {
+	deny := make(Denylist, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			return nil, fmt.Errorf("empty denylist entry")
+		}
+		deny = append(deny, entry)
+	}
+	return deny, nil
+}
+
+// deniesCategory returns the entry denying one of the categories, if any.
+func (d Denylist) deniesCategory(categories []string) (string, bool) {
+	for _, entry := range d {
+		for _, category := range categories {
+			if category != "" && strings.EqualFold(entry, category) {
+				return entry, true
+			}
+		}
+	}
+	return "", false
+}
+
+// denies returns the entry denying the license, if any. Category entries
+// are matched against the given categories when there are some, and
+// against the classification of the license otherwise.
+func (d Denylist) denies(license string, categories []string) (string, bool) {
+	if len(categories) == 0 {
+		categories = []string{classifyLicense(license)}
+	}
+	if entry, denied := d.deniesCategory(categories); denied {
+		return entry, true
+	}
+	upper := strings.ToUpper(license)
+	for _, entry := range d {
+		if strings.HasPrefix(upper, strings.ToUpper(entry)) {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// Denies reports whether the license expression is denied, i.e. whether
+// every alternative holds a denied license, and returns the entries which
+// denied it. The categories are the ones the source of the license already
+// set for the whole expression, e.g. the trivy license scanner, which
+// category entries are matched against instead of classifying each license.
+func (d Denylist) Denies(expr string, categories ...string) ([]string, bool) {
+	if len(d) == 0 {
+		return nil, false
+	}
+	var known []string
+	for _, category := range categories {
+		if category != "" {
+			known = append(known, category)
+		}
+	}
+	if entry, denied := d.deniesCategory(known); denied {
+		return []string{entry}, true
+	}
+
+	alts := alternatives(expr)
+	if len(alts) == 0 {
+		// Packages without a license are only denied by the unknown
+		// category.
+		if len(known) == 0 {
+			known = []string{CategoryUnknown}
+		}
+		if entry, denied := d.deniesCategory(known); denied {
+			return []string{entry}, true
+		}
+		return nil, false
+	}
+
+	entries := make(map[string]bool)
+	for _, alt := range alts {
+		deniedAlt := false
+		for _, license := range alt {
+			if entry, denied := d.denies(license, known); denied {
+				entries[entry] = true
+				deniedAlt = true
+			}
+		}
+		if !deniedAlt {
+			return nil, false
+		}
+	}
+	list := make([]string, 0, len(entries))
+	for entry := range entries {
+		list = append(list, entry)
+	}
+	sort.Strings(list)
+	return list, true
+}
+
+// LicensedPackage is a package along with its license expression.
+type LicensedPackage struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	License  string `json:"license"`
+	Category string `json:"category"`
+	// SourceCategory is the category set by the source of the license in
+	// its own classification, e.g. forbidden by the trivy license scanner.
+	SourceCategory string `json:"sourceCategory,omitempty"`
+}
+
+// LicenseGroup lists the packages released under a license.
+type LicenseGroup struct {
+	License  string   `json:"license"`
+	Category string   `json:"category"`
+	Packages []string `json:"packages"`
+}
+
+// Violation is a package released under a denied license.
+type Violation struct {
+	LicensedPackage
+	DeniedBy []string `json:"deniedBy"`
+}
+
+// LicenseReport classifies the licenses of packages and flags the ones
+// violating the denylist.
+type LicenseReport struct {
+	Categories map[string]int `json:"categories"`
+	Licenses   []LicenseGroup `json:"licenses"`
+	Violations []Violation    `json:"violations"`
+	Compliant  bool           `json:"compliant"`
+}
+
+// NewLicenseReport builds the license report of the packages. Packages
+// whose category is already set, e.g. by the trivy license scanner, keep
+// it, others are classified from their license.
+func NewLicenseReport(packages []LicensedPackage, deny Denylist) LicenseReport {
+	report := LicenseReport{
+		Categories: map[string]int{CategoryPermissive: 0, CategoryCopyleft: 0, CategoryUnknown: 0},
+		Licenses:   []LicenseGroup{},
+		Violations: []Violation{},
+	}
+
+	groups := make(map[string]*LicenseGroup)
+	for _, pkg := range packages {
+		// The categories set by the source take precedence over the
+		// classification of the license by the denylist.
+		categories := []string{pkg.Category, pkg.SourceCategory}
+		if pkg.Category == "" {
+			pkg.Category = ClassifyLicense(pkg.License)
+		}
+		report.Categories[pkg.Category]++
+
+		group, exist := groups[pkg.License]
+		if !exist {
+			group = &LicenseGroup{License: pkg.License, Category: pkg.Category, Packages: []string{}}
+			groups[pkg.License] = group
+		}
+		name := pkg.Name
+		if pkg.Version != "" {
+			name += "@" + pkg.Version
+		}
+		group.Packages = append(group.Packages, name)
+
+		if entries, denied := deny.Denies(pkg.License, categories...); denied {
+			report.Violations = append(report.Violations, Violation{LicensedPackage: pkg, DeniedBy: entries})
+		}
+	}
+
+	for _, group := range groups {
+		sort.Strings(group.Packages)
+		report.Licenses = append(report.Licenses, *group)
+	}
+	sort.Slice(report.Licenses, func(i, j int) bool {
+		a, b := report.Licenses[i], report.Licenses[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.License < b.License
+	})
+	sort.Slice(report.Violations, func(i, j int) bool {
+		return report.Violations[i].Name < report.Violations[j].Name
+	})
+	report.Compliant = len(report.Violations) == 0
+	return report
+}
+
+// LicensedPackages lists the packages of the document along with their
+// licenses, joining several licenses of a package as all applying.
+func (d *Document) LicensedPackages() []LicensedPackage {
+	packages := make([]LicensedPackage, 0, len(d.Packages))
+	for _, pkg := range d.Packages {
+		license := strings.Join(pkg.Licenses, " AND ")
+		if len(pkg.Licenses) > 1 {
+			license = "(" + strings.Join(pkg.Licenses, ") AND (") + ")"
+		}
+		packages = append(packages, LicensedPackage{Name: pkg.Name, Version: pkg.Version, License: license})
+	}
+	return packages
+}
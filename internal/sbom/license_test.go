@@ -0,0 +1,123 @@
+package sbom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlternatives(t *testing.T) {
+	tests := []struct {
+		expr string
+		want [][]string
+	}{
+		{"", nil},
+		{"MIT", [][]string{{"MIT"}}},
+		{"MIT OR Apache-2.0", [][]string{{"MIT"}, {"Apache-2.0"}}},
+		{"MIT AND Zlib", [][]string{{"MIT", "Zlib"}}},
+		{"(MIT OR BSD-3-Clause) AND Zlib", [][]string{{"MIT", "Zlib"}, {"BSD-3-Clause", "Zlib"}}},
+		{"Zlib AND (MIT OR BSD-3-Clause)", [][]string{{"Zlib", "MIT"}, {"Zlib", "BSD-3-Clause"}}},
+		{"MIT OR GPL-2.0 AND Zlib", [][]string{{"MIT"}, {"GPL-2.0", "Zlib"}}},
+		{"((MIT))", [][]string{{"MIT"}}},
+		// Exceptions are ignored.
+		{"GPL-2.0-only WITH Classpath-exception-2.0", [][]string{{"GPL-2.0-only"}}},
+		{"GPL-2.0-only with Classpath-exception-2.0 OR MIT", [][]string{{"GPL-2.0-only"}, {"MIT"}}},
+		{"GPL-2.0-only WITH", [][]string{{"GPL-2.0-only"}}},
+		// Lower case operators.
+		{"mit or apache-2.0", [][]string{{"mit"}, {"apache-2.0"}}},
+		{"MIT and Zlib", [][]string{{"MIT", "Zlib"}}},
+		{"MIT Or Zlib", [][]string{{"MIT"}, {"Zlib"}}},
+		// Multi-word names.
+		{"Apache License 2.0", [][]string{{"Apache License 2.0"}}},
+		{"Apache License 2.0 and GNU GPL v2", [][]string{{"Apache License 2.0", "GNU GPL v2"}}},
+		{"(Apache License 2.0 OR MIT License)", [][]string{{"Apache License 2.0"}, {"MIT License"}}},
+		// Unbalanced parentheses.
+		{"(MIT OR GPL-2.0", [][]string{{"MIT"}, {"GPL-2.0"}}},
+		{"MIT) OR GPL-2.0", [][]string{{"MIT"}, {"GPL-2.0"}}},
+		{"(MIT AND (Zlib OR ISC)", [][]string{{"MIT", "Zlib"}, {"MIT", "ISC"}}},
+		{"()", nil},
+		// Dangling operators.
+		{"MIT AND", [][]string{{"MIT"}}},
+		{"OR MIT", [][]string{{"MIT"}}},
+	}
+	for _, tt := range tests {
+		if got := alternatives(tt.expr); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("alternatives(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyLicense(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", CategoryUnknown},
+		{"MIT", CategoryPermissive},
+		{"Apache License 2.0", CategoryPermissive},
+		{"GPL-3.0-or-later", CategoryCopyleft},
+		{"GPL v2", CategoryCopyleft},
+		{"LGPL-2.1-only", CategoryCopyleft},
+		{"Proprietary", CategoryUnknown},
+		{"MIT OR GPL-2.0", CategoryPermissive},
+		{"mit or gpl-2.0", CategoryPermissive},
+		{"MIT AND GPL-2.0", CategoryCopyleft},
+		{"MIT AND Proprietary", CategoryUnknown},
+		{"GPL-2.0 OR Proprietary", CategoryUnknown},
+		{"GPL-2.0 OR LGPL-2.1", CategoryCopyleft},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", CategoryCopyleft},
+		{"(MIT OR GPL-2.0", CategoryPermissive},
+	}
+	for _, tt := range tests {
+		if got := ClassifyLicense(tt.expr); got != tt.want {
+			t.Errorf("ClassifyLicense(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestDenylistDenies(t *testing.T) {
+	tests := []struct {
+		deny       Denylist
+		expr       string
+		categories []string
+		want       []string
+	}{
+		{nil, "GPL-3.0", nil, nil},
+		{Denylist{"GPL-3.0"}, "GPL-3.0-only", nil, []string{"GPL-3.0"}},
+		{Denylist{"gpl"}, "GPL-2.0", nil, []string{"gpl"}},
+		{Denylist{"GPL-3.0"}, "GPL-2.0", nil, nil},
+		{Denylist{"AGPL"}, "MIT OR AGPL-3.0", nil, nil},
+		{Denylist{"AGPL"}, "MIT AND AGPL-3.0", nil, []string{"AGPL"}},
+		{Denylist{"AGPL", "GPL"}, "AGPL-3.0 OR GPL-2.0", nil, []string{"AGPL", "GPL"}},
+		{Denylist{"copyleft"}, "LGPL-2.1", nil, []string{"copyleft"}},
+		{Denylist{"copyleft"}, "MIT", nil, nil},
+		{Denylist{"unknown"}, "", nil, []string{"unknown"}},
+		{Denylist{"copyleft"}, "", nil, nil},
+		// Categories set by the source apply to the whole expression.
+		{Denylist{"copyleft"}, "Custom", []string{"copyleft"}, []string{"copyleft"}},
+		{Denylist{"forbidden"}, "Custom", []string{"copyleft", "forbidden"}, []string{"forbidden"}},
+		{Denylist{"copyleft"}, "GPL-2.0", []string{"permissive"}, nil},
+		{Denylist{"GPL"}, "GPL-2.0", []string{"permissive"}, []string{"GPL"}},
+		{Denylist{"unknown"}, "", []string{"permissive"}, nil},
+	}
+	for _, tt := range tests {
+		got, denied := tt.deny.Denies(tt.expr, tt.categories...)
+		if denied != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q.Denies(%q, %q) = %q, %t, want %q", tt.deny, tt.expr, tt.categories, got, denied, tt.want)
+		}
+	}
+}
+
+func TestParseDenylist(t *testing.T) {
+	deny, err := ParseDenylist([]string{" AGPL ", "copyleft"})
+	if err != nil {
+		t.Fatalf("ParseDenylist failed: %v", err)
+	}
+	if want := (Denylist{"AGPL", "copyleft"}); !reflect.DeepEqual(deny, want) {
+		t.Errorf("ParseDenylist = %q, want %q", deny, want)
+	}
+	for _, entries := range [][]string{{""}, {"AGPL", "  "}} {
+		if _, err := ParseDenylist(entries); err == nil {
+			t.Errorf("ParseDenylist(%q) succeeded, want an error", entries)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Package sbom parses SPDX and CycloneDX JSON documents into a common
+// package model, which the SBOM tools query instead of returning the raw
+// documents.
+package sbom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Document formats.
+const (
+	FormatSPDX      = "spdx"
+	FormatCycloneDX = "cyclonedx"
+)
+
+// UnknownEcosystem is the ecosystem of packages without a package URL.
+const UnknownEcosystem = "unknown"
+
+// ErrUnknownFormat is returned when the document is neither an SPDX nor a
+// CycloneDX JSON document.
+var ErrUnknownFormat = errors.New("unknown SBOM format, expected SPDX or CycloneDX JSON")
+
+// Package is a package listed by an SBOM.
+type Package struct {
+	Name      string   `json:"name"`
+	Version   string   `json:"version,omitempty"`
+	Ecosystem string   `json:"ecosystem"`
+	PURL      string   `json:"purl,omitempty"`
+	Licenses  []string `json:"licenses,omitempty"`
+}
+
+// Document is a parsed SBOM.
+type Document struct {
+	Format   string    `json:"format"`
+	Packages []Package `json:"packages"`
+}
+
+type spdxDocument struct {
+	SPDXVersion string        `json:"spdxVersion"`
+	Packages    []spdxPackage `json:"packages"`
+}
+
+type spdxPackage struct {
+	Name             string `json:"name"`
+	VersionInfo      string `json:"versionInfo"`
+	LicenseConcluded string `json:"licenseConcluded"`
+	LicenseDeclared  string `json:"licenseDeclared"`
+	ExternalRefs     []struct {
+		ReferenceType    string `json:"referenceType"`
+		ReferenceLocator string `json:"referenceLocator"`
+	} `json:"externalRefs"`
+}
+
+type cycloneDXDocument struct {
+	BOMFormat  string               `json:"bomFormat"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	PURL     string `json:"purl"`
+	Licenses []struct {
+		License *struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// Parse parses an SPDX or CycloneDX JSON document.
+func Parse(data []byte) (*Document, error) {
+	var probe struct {
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse SBOM: %w", err)
+	}
+
+	switch {
+	case probe.SPDXVersion != "":
+		return parseSPDX(data)
+	case probe.BOMFormat == "CycloneDX":
+		return parseCycloneDX(data)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func parseSPDX(data []byte) (*Document, error) {
+	var spdx spdxDocument
+	if err := json.Unmarshal(data, &spdx); err != nil {
+		return nil, fmt.Errorf("failed to parse SPDX document: %w", err)
+	}
+
+	doc := &Document{Format: FormatSPDX, Packages: []Package{}}
+	for _, p := range spdx.Packages {
+		pkg := Package{Name: p.Name, Version: p.VersionInfo}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				pkg.PURL = ref.ReferenceLocator
+				break
+			}
+		}
+		// The image itself is listed as a package, without a package URL
+		// or a version, along with its files.
+		if pkg.PURL == "" && pkg.Version == "" {
+			continue
+		}
+		pkg.Ecosystem = ecosystem(pkg.PURL)
+		license := p.LicenseDeclared
+		if !spdxLicenseSet(license) {
+			license = p.LicenseConcluded
+		}
+		if spdxLicenseSet(license) {
+			pkg.Licenses = []string{license}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+	}
+	return doc, nil
+}
+
+// spdxLicenseSet reports whether the SPDX license field holds a license.
+func spdxLicenseSet(license string) bool {
+	return license != "" && license != "NOASSERTION" && license != "NONE"
+}
+
+func parseCycloneDX(data []byte) (*Document, error) {
+	var cdx cycloneDXDocument
+	if err := json.Unmarshal(data, &cdx); err != nil {
+		return nil, fmt.Errorf("failed to parse CycloneDX document: %w", err)
+	}
+
+	doc := &Document{Format: FormatCycloneDX, Packages: []Package{}}
+	var walk func(components []cycloneDXComponent)
+	walk = func(components []cycloneDXComponent) {
+		for _, c := range components {
+			// Operating systems and files are components too, but not
+			// packages.
+			if c.Type == "library" || c.Type == "application" || c.Type == "framework" {
+				pkg := Package{
+					Name:      c.Name,
+					Version:   c.Version,
+					PURL:      c.PURL,
+					Ecosystem: ecosystem(c.PURL),
+				}
+				for _, l := range c.Licenses {
+					switch {
+					case l.Expression != "":
+						pkg.Licenses = append(pkg.Licenses, l.Expression)
+					case l.License != nil && l.License.ID != "":
+						pkg.Licenses = append(pkg.Licenses, l.License.ID)
+					case l.License != nil && l.License.Name != "":
+						pkg.Licenses = append(pkg.Licenses, l.License.Name)
+					}
+				}
+				doc.Packages = append(doc.Packages, pkg)
+			}
+			walk(c.Components)
+		}
+	}
+	walk(cdx.Components)
+	return doc, nil
+}
+
+// ecosystem returns the type of the package URL, e.g. npm, maven or deb.
+func ecosystem(purl string) string {
+	rest, found := strings.CutPrefix(purl, "pkg:")
+	if !found {
+		return UnknownEcosystem
+	}
+	purlType, _, _ := strings.Cut(rest, "/")
+	if purlType, err := url.PathUnescape(purlType); err == nil && purlType != "" {
+		return strings.ToLower(purlType)
+	}
+	return UnknownEcosystem
+}
+
+// ByEcosystem returns the packages of the ecosystem, or all the packages
+// when the ecosystem is empty.
+func (d *Document) ByEcosystem(eco string) []Package {
+	packages := []Package{}
+	for _, pkg := range d.Packages {
+		if eco == "" || strings.EqualFold(pkg.Ecosystem, eco) {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
+
+// Find returns the packages whose name contains the given name, ignoring
+// case, and whose version satisfies the constraint, when given.
+func (d *Document) Find(name string, constraint Constraint) []Package {
+	name = strings.ToLower(name)
+	packages := []Package{}
+	for _, pkg := range d.Packages {
+		if !strings.Contains(strings.ToLower(pkg.Name), name) {
+			continue
+		}
+		if !constraint.Check(pkg.Version) {
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+// LicenseUsage lists the packages released under a license.
+type LicenseUsage struct {
+	License  string   `json:"license"`
+	Count    int      `json:"count"`
+	Packages []string `json:"packages"`
+}
+
+// Licenses returns the licenses of the packages, the most used first.
+// Packages without a license are listed under an empty license.
+func (d *Document) Licenses() []LicenseUsage {
+	usages := make(map[string]*LicenseUsage)
+	add := func(license string, pkg Package) {
+		usage, exist := usages[license]
+		if !exist {
+			usage = &LicenseUsage{License: license, Packages: []string{}}
+			usages[license] = usage
+		}
+		usage.Count++
+		usage.Packages = append(usage.Packages, pkg.Name+"@"+pkg.Version)
+	}
+	for _, pkg := range d.Packages {
+		if len(pkg.Licenses) == 0 {
+			add("", pkg)
+		}
+		for _, license := range pkg.Licenses {
+			add(license, pkg)
+		}
+	}
+
+	list := make([]LicenseUsage, 0, len(usages))
+	for _, usage := range usages {
+		list = append(list, *usage)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].License < list[j].License
+	})
+	return list
+}
+
+// Counts holds the number of packages, in total and per ecosystem.
+type Counts struct {
+	Total      int            `json:"total"`
+	Ecosystems map[string]int `json:"ecosystems"`
+	Unlicensed int            `json:"unlicensed"`
+}
+
+// Count counts the packages of the document.
+func (d *Document) Count() Counts {
+	counts := Counts{Ecosystems: map[string]int{}}
+	for _, pkg := range d.Packages {
+		counts.Total++
+		counts.Ecosystems[pkg.Ecosystem]++
+		if len(pkg.Licenses) == 0 {
+			counts.Unlicensed++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,194 @@
+package sbom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// In this file, we define version constraints, e.g. ">=2.0.0, <2.17.1",
+// which are checked against package versions of any ecosystem by
+// comparing their numeric and alphabetic segments in order.
+
+// comparators are the operators of a constraint, longest first, so >= is
+// matched before >.
+var comparators = []string{">=", "<=", "!=", ">", "<", "="}
+
+type condition struct {
+	op      string
+	version string
+}
+
+// Constraint is a set of conditions a version must all satisfy. The zero
+// value is satisfied by any version.
+type Constraint struct {
+	conditions []condition
+}
+
+// ParseConstraint parses conditions separated by commas or spaces, where
+// each condition is an operator followed by a version. A version without
+// an operator must match exactly.
+func ParseConstraint(s string) (Constraint, error) {
+	var c Constraint
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		op := "="
+		for _, candidate := range comparators {
+			if rest, found := strings.CutPrefix(field, candidate); found {
+				op, field = candidate, rest
+				break
+			}
+		}
+		// Allow a space between the operator and the version.
+		if field == "" {
+			if i+1 == len(fields) {
+				return c, fmt.Errorf("invalid version constraint %q: missing version after %s", s, op)
+			}
+			i++
+			field = fields[i]
+		}
+		c.conditions = append(c.conditions, condition{op: op, version: field})
+	}
+	return c, nil
+}
+
+// Check reports whether the version satisfies the constraint.
+func (c Constraint) Check(version string) bool {
+	for _, cond := range c.conditions {
+		cmp := CompareVersions(version, cond.version)
+		var ok bool
+		switch cond.op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// CompareVersions compares two versions segment by segment, where numeric
+// segments compare as numbers and others as strings, and returns -1, 0 or
+// 1. A leading v is ignored, and a version which is a prefix of another
+// is lower, e.g. 1.2 < 1.2.1, unless the other one goes on with letters,
+// which mark a pre-release, e.g. 2.0.0-rc1 < 2.0.0. The epochs of Debian
+// and RPM versions, e.g. the 1 of 1:2.3, take precedence over the rest,
+// and are 0 when missing.
+func CompareVersions(a, b string) int {
+	aEpoch, a := splitEpoch(a)
+	bEpoch, b := splitEpoch(b)
+	switch {
+	case aEpoch < bEpoch:
+		return -1
+	case aEpoch > bEpoch:
+		return 1
+	}
+
+	as, bs := versionSegments(a), versionSegments(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if cmp := compareSegments(as[i], bs[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -prefixOrder(bs[len(as)])
+	case len(as) > len(bs):
+		return prefixOrder(as[len(bs)])
+	default:
+		return 0
+	}
+}
+
+// splitEpoch splits the epoch off the version, when it has one.
+func splitEpoch(version string) (uint64, string) {
+	version = strings.TrimSpace(version)
+	prefix, rest, found := strings.Cut(version, ":")
+	if !found {
+		return 0, version
+	}
+	epoch, err := strconv.ParseUint(prefix, 10, 64)
+	if err != nil {
+		return 0, version
+	}
+	return epoch, rest
+}
+
+// prefixOrder compares a version to one of its prefixes, given the first
+// segment following the prefix.
+func prefixOrder(next string) int {
+	if _, err := strconv.ParseUint(next, 10, 64); err != nil {
+		return -1
+	}
+	return 1
+}
+
+// versionSegments splits the version into runs of digits and letters,
+// dropping the separators.
+func versionSegments(version string) []string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	var segments []string
+	var current strings.Builder
+	digits := false
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range version {
+		switch {
+		case unicode.IsDigit(r):
+			if !digits {
+				flush()
+			}
+			digits = true
+			current.WriteRune(r)
+		case unicode.IsLetter(r):
+			if digits {
+				flush()
+			}
+			digits = false
+			current.WriteRune(unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return segments
+}
+
+func compareSegments(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		switch {
+		case an < bn:
+			return -1
+		case an > bn:
+			return 1
+		}
+		return 0
+	// Numbers sort after letters, so 1.0.rc1 < 1.0.1.
+	case aErr == nil:
+		return 1
+	case bErr == nil:
+		return -1
+	default:
+		return strings.Compare(a, b)
+	}
+}
@@ -0,0 +1,66 @@
+package sbom
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.10", -1},
+		{"1.10", "1.9", 1},
+		{"1.2", "1.2.1", -1},
+		{"2.0.0-rc1", "2.0.0", -1},
+		{"1.0.rc1", "1.0.1", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.1.1k", "1.1.1l", -1},
+		{"1:2.3", "2.4", 1},
+		{"2.4", "1:2.3", -1},
+		{"0:2.3", "2.3", 0},
+		{"1:2.3", "1:2.4", -1},
+		{"2:1.0", "1:9.9", 1},
+		{"1:2.3-1ubuntu1", "1:2.3-1ubuntu2", -1},
+		{"2.30-r1", "2.30-r0", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"", "1.0.0", true},
+		{">=2.0.0, <2.17.1", "2.14.1", true},
+		{">=2.0.0, <2.17.1", "2.17.1", false},
+		{">= 2.0.0 < 2.17.1", "1.9", false},
+		{"1.2.3", "1.2.3", true},
+		{"!=1.2.3", "1.2.3", false},
+		{"<1:1.0", "2.0", true},
+		{">=1:1.0", "9.9", false},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q) failed: %v", tt.constraint, err)
+		}
+		if got := c.Check(tt.version); got != tt.want {
+			t.Errorf("ParseConstraint(%q).Check(%q) = %t, want %t", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, constraint := range []string{">=", "<2.0, >"} {
+		if _, err := ParseConstraint(constraint); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want an error", constraint)
+		}
+	}
+}
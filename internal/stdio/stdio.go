@@ -0,0 +1,258 @@
+// Package stdio serves an MCP server over stdio, like server.ServeStdio,
+// while handling the requests the MCP server doesn't support itself, e.g.
+// resources/subscribe, sending notifications outside of requests, and
+// answering tool calls failing on their arguments with invalid params
+// errors.
+package stdio
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RequestHandler handles the params of a request and returns its result.
+type RequestHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
+
+// Server serves an MCP server over stdio. Requests with a handler are
+// answered by it, and the others by the MCP server.
+type Server struct {
+	server    *server.MCPServer
+	handlers  map[string]RequestHandler
+	errLogger *log.Logger
+
+	// sessionCtx is the context of the stdio session, which is needed to
+	// send notifications to the client.
+	mu         sync.Mutex
+	sessionCtx context.Context
+}
+
+// NewServer creates a stdio server wrapping the MCP server.
+func NewServer(s *server.MCPServer) *Server {
+	return &Server{
+		server:    s,
+		handlers:  make(map[string]RequestHandler),
+		errLogger: log.New(os.Stderr, "", log.LstdFlags),
+	}
+}
+
+// Handle sets the handler of the requests of the given method.
+func (s *Server) Handle(method string, handler RequestHandler) {
+	s.handlers[method] = handler
+}
+
+// Notify sends a notification to the client. It fails until the client
+// initialized the session.
+func (s *Server) Notify(method string, params map[string]interface{}) error {
+	s.mu.Lock()
+	ctx := s.sessionCtx
+	s.mu.Unlock()
+	if ctx == nil {
+		return errors.New("stdio session not started")
+	}
+	return s.server.SendNotificationToClient(ctx, method, params)
+}
+
+// lockedWriter serializes the writes of the responses and notifications,
+// which are written from several goroutines, so messages don't interleave.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// callErrorKey is the context key of the error of a tool call, recorded by
+// RecordErrors.
+type callErrorKey struct{}
+
+// RecordErrors is the tool handler middleware recording the error of each
+// tool call, so the calls failing with params.ErrInvalidParams are answered
+// with an invalid params error, where mcp-go answers every handler error
+// with an internal error. It must be the first middleware of the MCP server
+// to see the errors of the others.
+func RecordErrors(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, req)
+		if callErr, ok := ctx.Value(callErrorKey{}).(*error); ok {
+			*callErr = err
+		}
+		return result, err
+	}
+}
+
+// callTool answers the tool call through the MCP server, turning the
+// internal error into an invalid params error when the call failed on its
+// arguments.
+func (s *Server) callTool(ctx context.Context, line []byte) mcp.JSONRPCMessage {
+	var callErr error
+	response := s.server.HandleMessage(context.WithValue(ctx, callErrorKey{}, &callErr), line)
+	if !errors.Is(callErr, params.ErrInvalidParams) {
+		return response
+	}
+	switch r := response.(type) {
+	case mcp.JSONRPCError:
+		r.Error.Code = mcp.INVALID_PARAMS
+		return r
+	case *mcp.JSONRPCError:
+		r.Error.Code = mcp.INVALID_PARAMS
+	}
+	return response
+}
+
+// request is the part of a JSON-RPC message needed to route it.
+type request struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Listen reads JSON-RPC messages from stdin and writes the responses to
+// stdout, until the context is cancelled or stdin is closed.
+func (s *Server) Listen(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	out := &lockedWriter{w: stdout}
+
+	stdioServer := server.NewStdioServer(s.server)
+	stdioServer.SetErrorLogger(s.errLogger)
+	stdioServer.SetContextFunc(func(ctx context.Context) context.Context {
+		s.mu.Lock()
+		s.sessionCtx = ctx
+		s.mu.Unlock()
+		return ctx
+	})
+
+	// Messages which aren't handled here are piped to the MCP server.
+	pipeReader, pipeWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- stdioServer.Listen(ctx, pipeReader, out)
+	}()
+
+	go func() {
+		// Tool calls are answered concurrently, so the other requests,
+		// e.g. subscriptions, don't wait for a long call. The pipe is only
+		// closed once they're answered, which stops the MCP server.
+		var inflight sync.WaitGroup
+		reader := bufio.NewReader(stdin)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 && !s.intercept(ctx, line, out, &inflight) {
+				if _, err := pipeWriter.Write(line); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				inflight.Wait()
+				pipeWriter.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	return <-done
+}
+
+// intercept answers the message when it's a tool call, in a goroutine
+// tracked by inflight, or a request with a handler, in order, and reports
+// whether it does.
+func (s *Server) intercept(ctx context.Context, line []byte, out io.Writer, inflight *sync.WaitGroup) bool {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil || len(req.ID) == 0 {
+		return false
+	}
+	if req.Method == string(mcp.MethodToolsCall) {
+		// The context of the session carries the client session, which the
+		// tools need to send progress notifications.
+		s.mu.Lock()
+		sessionCtx := s.sessionCtx
+		s.mu.Unlock()
+		if sessionCtx == nil {
+			return false
+		}
+		inflight.Add(1)
+		go func() {
+			defer inflight.Done()
+			s.writeResponse(out, s.callTool(sessionCtx, line))
+		}()
+		return true
+	}
+
+	handler, exist := s.handlers[req.Method]
+	if !exist {
+		return false
+	}
+	s.writeResponse(out, s.handle(ctx, req, handler))
+	return true
+}
+
+// handle answers the request with its handler.
+func (s *Server) handle(ctx context.Context, req request, handler RequestHandler) interface{} {
+	result, err := handler(ctx, req.Params)
+	switch {
+	case errors.Is(err, params.ErrInvalidParams):
+		return errorResponse(req.ID, mcp.INVALID_PARAMS, err)
+	case err != nil:
+		return errorResponse(req.ID, mcp.INTERNAL_ERROR, err)
+	default:
+		return map[string]interface{}{
+			"jsonrpc": mcp.JSONRPC_VERSION,
+			"id":      req.ID,
+			"result":  result,
+		}
+	}
+}
+
+// writeResponse writes the response as a line of JSON.
+func (s *Server) writeResponse(out io.Writer, response interface{}) {
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		s.errLogger.Printf("Error encoding response: %v", err)
+		return
+	}
+	if _, err := fmt.Fprintf(out, "%s\n", responseBytes); err != nil {
+		s.errLogger.Printf("Error writing response: %v", err)
+	}
+}
+
+func errorResponse(id json.RawMessage, code int, err error) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      id,
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": err.Error(),
+		},
+	}
+}
+
+// ServeStdio serves the server over the standard input and output, until
+// stdin is closed or the process receives SIGTERM or SIGINT.
+func ServeStdio(s *Server) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	return s.Listen(ctx, os.Stdin, os.Stdout)
+}
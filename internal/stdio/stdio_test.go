@@ -0,0 +1,64 @@
+package stdio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+)
+
+func TestCallToolErrorCodes(t *testing.T) {
+	mcpServer := server.NewMCPServer("test", "1.0.0",
+		server.WithToolCapabilities(false),
+		server.WithToolHandlerMiddleware(RecordErrors),
+	)
+	tool := mcp.NewTool("inspect", mcp.WithString("container", mcp.Required(), params.Format(params.ContainerFormat)))
+	mcpServer.AddTool(tool, params.Validate(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		container, _ := req.Params.Arguments["container"].(string)
+		if container == "broken" {
+			return nil, errors.New("failed to inspect container")
+		}
+		if container == "missing" {
+			return nil, fmt.Errorf("%w: no such container %q", params.ErrInvalidParams, container)
+		}
+		return mcp.NewToolResultText("ok"), nil
+	}))
+	s := NewServer(mcpServer)
+
+	tests := []struct {
+		name      string
+		arguments string
+		code      int
+	}{
+		{"invalid argument", `{"container": "-web"}`, mcp.INVALID_PARAMS},
+		{"missing argument", `{}`, mcp.INVALID_PARAMS},
+		{"invalid params from the handler", `{"container": "missing"}`, mcp.INVALID_PARAMS},
+		{"internal error", `{"container": "broken"}`, mcp.INTERNAL_ERROR},
+		{"success", `{"container": "web"}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "inspect", "arguments": ` + tt.arguments + `}}`)
+			response := s.callTool(context.Background(), line)
+
+			var code int
+			switch r := response.(type) {
+			case mcp.JSONRPCError:
+				code = r.Error.Code
+			case *mcp.JSONRPCError:
+				code = r.Error.Code
+			case mcp.JSONRPCResponse, *mcp.JSONRPCResponse:
+			default:
+				t.Fatalf("callTool returned %T", response)
+			}
+			if code != tt.code {
+				t.Errorf("callTool returned code %d, want %d", code, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package trivy
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// In this file, we define the cache of image scan results. Results are
+// keyed by the image ID, which is the digest of its configuration, along
+// with the vulnerability DB version and the scan options, so a cached
+// report is only returned for the very same scan of an unchanged image.
+
+// DefaultResultTTL is the default time scan results are cached for.
+const DefaultResultTTL = time.Hour
+
+type cacheEntry struct {
+	report  []byte
+	expires time.Time
+}
+
+// resultCache holds the JSON reports of previous scans. Reports are kept
+// encoded, so each caller parses its own copy and may modify it.
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+var results = &resultCache{entries: make(map[string]cacheEntry)}
+
+func (c *resultCache) get(key string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exist := c.entries[key]
+	if !exist || now.After(entry.expires) {
+		return nil, false
+	}
+	return entry.report, true
+}
+
+func (c *resultCache) put(key string, report []byte, now time.Time, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Expired entries are dropped on insert, so the cache doesn't grow
+	// over a long session.
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{report: report, expires: now.Add(ttl)}
+}
+
+// cacheKey returns the cache key of the scan, or false when the scan
+// can't be cached, i.e. when it isn't an image scan, when caching is
+// disabled, or when the image isn't present locally and its ID can't be
+// resolved without pulling it.
+func cacheKey(ctx context.Context, command, target string, opts ScanOptions) (string, bool) {
+	if command != "image" || config.ResultTTL <= 0 {
+		return "", false
+	}
+
+	idBytes, err := exec.CommandContext(ctx, "docker", "image", "inspect", "--format", "{{.Id}}", target).Output()
+	if err != nil {
+		return "", false
+	}
+	dbVersion, ok := vulnDBVersion(ctx)
+	if !ok {
+		return "", false
+	}
+
+	key := []string{command, strings.TrimSpace(string(idBytes)), dbVersion}
+	return strings.Join(append(key, opts.args()...), "\x00"), true
+}
+
+// vulnDBVersion identifies the vulnerability DB used by the scans by its
+// schema version and update time.
+func vulnDBVersion(ctx context.Context) (string, bool) {
+	info, err := readVersion(ctx)
+	if err != nil || info.VulnerabilityDB == nil {
+		return "", false
+	}
+	db := info.VulnerabilityDB
+	return strings.Join([]string{
+		config.Server,
+		info.Version,
+		strconv.Itoa(db.Version),
+		db.UpdatedAt.UTC().Format(time.RFC3339),
+	}, "/"), true
+}
@@ -0,0 +1,110 @@
+package trivy
+
+import (
+	"os"
+	"time"
+)
+
+// Config holds the serve time configuration shared by all the trivy tools.
+type Config struct {
+	// CacheDir is the trivy cache directory, holding the vulnerability DB.
+	CacheDir string
+	// SkipDBUpdate uses the vulnerability DB already present in the cache,
+	// instead of downloading it.
+	SkipDBUpdate bool
+	// OfflineScan avoids any request to the internet during the scan.
+	OfflineScan bool
+	// DBRepository is the OCI repository the vulnerability DB is
+	// downloaded from, e.g. a mirror in an air-gapped network.
+	DBRepository string
+	// ResultTTL is the time image scan results are cached for, zero
+	// disables the cache.
+	ResultTTL time.Duration
+	// Server is the URL of a trivy server the scans are delegated to, so
+	// this instance doesn't need a vulnerability DB of its own.
+	Server string
+	// Token authenticates the scans against the trivy server.
+	Token string
+	// TokenHeader is the HTTP header the token is sent in.
+	TokenHeader string
+}
+
+// config is the configuration used by the trivy tools.
+var config Config
+
+// Configure sets the configuration used by the trivy tools. It's meant to
+// be called once, before the tools are added to the server.
+func Configure(cfg Config) {
+	config = cfg
+}
+
+// vulnDBCommands are the trivy commands which make use of the
+// vulnerability DB.
+var vulnDBCommands = map[string]bool{
+	"image":  true,
+	"fs":     true,
+	"rootfs": true,
+	"repo":   true,
+	"sbom":   true,
+}
+
+// scansMisconfig reports whether the trivy command scans for
+// misconfigurations, which makes use of the checks bundle.
+func scansMisconfig(command string, scanners []string) bool {
+	if command == "config" {
+		return true
+	}
+	for _, scanner := range scanners {
+		if scanner == "misconfig" {
+			return true
+		}
+	}
+	return false
+}
+
+// args returns the trivy flags of the configuration which apply to the
+// given trivy command, run with the given scanners.
+func (c Config) args(command string, scanners []string) []string {
+	var args []string
+	if c.CacheDir != "" {
+		args = append(args, "--cache-dir", c.CacheDir)
+	}
+	// The checks bundle is downloaded like the vulnerability DB, and the
+	// checks embedded in trivy are used instead when it's skipped. Checks
+	// always run locally, even in client mode.
+	if (c.SkipDBUpdate || c.OfflineScan) && scansMisconfig(command, scanners) {
+		args = append(args, "--skip-check-update")
+	}
+	if !vulnDBCommands[command] {
+		return args
+	}
+	// In client mode, the vulnerability DB is the one of the server.
+	if c.Server != "" {
+		args = append(args, "--server", c.Server)
+		if c.TokenHeader != "" {
+			args = append(args, "--token-header", c.TokenHeader)
+		}
+		return args
+	}
+	if c.SkipDBUpdate {
+		args = append(args, "--skip-db-update", "--skip-java-db-update")
+	}
+	if c.OfflineScan {
+		args = append(args, "--offline-scan")
+	}
+	if c.DBRepository != "" {
+		args = append(args, "--db-repository", c.DBRepository)
+	}
+	return args
+}
+
+// env returns the environment of the trivy processes. The token is passed
+// in the environment rather than as a flag, so it doesn't show up in the
+// process list.
+func (c Config) env() []string {
+	env := os.Environ()
+	if c.Server != "" && c.Token != "" {
+		env = append(env, "TRIVY_TOKEN="+c.Token)
+	}
+	return env
+}
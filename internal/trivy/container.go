@@ -0,0 +1,223 @@
+package trivy
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the trivy_container tool, which exports the
+// filesystem of a container, including the changes made at runtime, e.g.
+// packages installed with docker_exec, and scans it as a root filesystem.
+
+var ContainerTool = mcp.NewTool("trivy_container",
+	mcp.WithDescription("Scan the filesystem of a container, including changes made at runtime, for vulnerabilities"),
+	mcp.WithString("containerID",
+		mcp.Required(),
+		mcp.Description("The ID of the container to scan"),
+		params.Format(params.ContainerFormat),
+	),
+	severityOption(),
+	ignoreUnfixedOption(),
+	scannersOption(),
+	pkgTypesOption(),
+	formatOption("table", "json", "sarif", "cyclonedx"),
+	topOption(),
+	fullReportOption(),
+	sarifOutputOption(),
+	sarifResourceOption(),
+	output.StoreOption(),
+)
+
+var containerScan = scanTool{
+	command:   "rootfs",
+	targetArg: "containerID",
+	vulnScan:  true,
+	prepare:   exportContainer,
+}
+
+// ContainerHandler is the handler function that handles container scan
+// requests.
+func ContainerHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return containerScan.handler()(ctx, req)
+}
+
+// exportContainer exports the filesystem of the container into a
+// temporary directory and returns its path, along with a function
+// removing it once the scan is done.
+func exportContainer(ctx context.Context, containerID string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "trivy-container-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+
+	exportCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	exportCmd := exec.CommandContext(exportCtx, "docker", "export", containerID)
+	exportCmd.Stderr = &stderr
+	stdout, err := exportCmd.StdoutPipe()
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to export container %s: %w", containerID, err)
+	}
+	if err := exportCmd.Start(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to export container %s: %w", containerID, err)
+	}
+
+	if err := extractTar(stdout, dir); err != nil {
+		cancel()
+		exportCmd.Wait()
+		cleanup()
+		return "", nil, fmt.Errorf("failed to extract filesystem of container %s: %w \n %s", containerID, err, stderr.String())
+	}
+	if err := exportCmd.Wait(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to export container %s: %w \n %s", containerID, err, stderr.String())
+	}
+	return dir, cleanup, nil
+}
+
+// extractTar extracts the tar stream into dir. Files are created through
+// an os.Root and symbolic links are rewritten to point inside dir, so a
+// malicious archive can never write or point outside of it.
+func extractTar(r io.Reader, dir string) error {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if name == "" {
+			continue
+		}
+		if err := workspace.MkdirAll(root, path.Dir(name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := workspace.MkdirAll(root, name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			// A parent which is a dangling symbolic link is skipped.
+			if err := writeFile(root, name, tr, header.FileInfo().Mode()); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		case tar.TypeLink:
+			source, err := root.Open(strings.TrimPrefix(path.Clean("/"+header.Linkname), "/"))
+			if err != nil {
+				// The link target could not be extracted, skip the link.
+				continue
+			}
+			err = writeFile(root, name, source, header.FileInfo().Mode())
+			source.Close()
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		case tar.TypeSymlink:
+			// The target is rewritten against the parent of the entry as
+			// named, which only holds when no parent is a symbolic link:
+			// the link would be created elsewhere, where the rewritten
+			// target could point outside of dir.
+			if symlinkParent(root, name) {
+				continue
+			}
+			if err := os.Symlink(symlinkTarget(name, header.Linkname), filepath.Join(dir, filepath.FromSlash(name))); err != nil && !os.IsExist(err) && !os.IsNotExist(err) {
+				return err
+			}
+		default:
+			// Devices, fifos and other special files are not scanned.
+		}
+	}
+}
+
+// symlinkParent reports whether one of the parents of name in root is a
+// symbolic link, or can't be checked.
+func symlinkParent(root *os.Root, name string) bool {
+	parent := ""
+	for _, part := range strings.Split(path.Dir(name), "/") {
+		if part == "." {
+			break
+		}
+		parent = path.Join(parent, part)
+		info, err := root.Lstat(parent)
+		if os.IsNotExist(err) {
+			return false
+		}
+		if err != nil || info.Mode()&os.ModeSymlink != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// symlinkTarget rewrites the target of the symbolic link name, so it's
+// relative to the link and resolves inside the extracted filesystem, the
+// same way it would resolve inside the container.
+func symlinkTarget(name, target string) string {
+	linkDir := path.Dir("/" + name)
+	resolved := target
+	if !path.IsAbs(target) {
+		resolved = path.Join(linkDir, target)
+	}
+	// Cleaning a rooted path never climbs above the root.
+	resolved = path.Clean("/" + resolved)
+
+	relative, err := filepath.Rel(linkDir, resolved)
+	if err != nil {
+		return "."
+	}
+	return filepath.ToSlash(relative)
+}
+
+// writeFile creates the regular file name in root with the content of r.
+// The file is always readable by the owner, so trivy can scan it.
+func writeFile(root *os.Root, name string, r io.Reader, mode os.FileMode) error {
+	file, err := root.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0o400)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WithContainerTool adds the container tool to the given mcp server
+func WithContainerTool(srv *server.MCPServer) *server.MCPServer {
+	srv.AddTool(ContainerTool, params.Validate(ContainerTool, ContainerHandler))
+	return srv
+}
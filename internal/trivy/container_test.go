@@ -0,0 +1,191 @@
+package trivy
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarEntry is an entry of a test archive.
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	content  string
+}
+
+func buildTar(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:     entry.name,
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+			Mode:     0o644,
+			Size:     int64(len(entry.content)),
+		}
+		if entry.typeflag == tar.TypeDir {
+			header.Mode = 0o755
+		}
+		if entry.typeflag != tar.TypeReg {
+			header.Size = 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Size > 0 {
+			if _, err := tw.Write([]byte(entry.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTarStaysInside(t *testing.T) {
+	base := t.TempDir()
+	outside := filepath.Join(base, "outside")
+	if err := os.Mkdir(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(outside, "secret")
+	if err := os.WriteFile(secret, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		// files are the contents expected inside dir, by path.
+		files map[string]string
+		// missing are the paths, inside or outside dir, which must not
+		// exist after the extraction.
+		missing []string
+	}{
+		{
+			name:    "parent entry",
+			entries: []tarEntry{{name: "../escaped", typeflag: tar.TypeReg, content: "x"}},
+			files:   map[string]string{"escaped": "x"},
+			missing: []string{filepath.Join(base, "escaped")},
+		},
+		{
+			name:    "nested parent entry",
+			entries: []tarEntry{{name: "etc/../../../outside/secret", typeflag: tar.TypeReg, content: "overwritten"}},
+			files:   map[string]string{"outside/secret": "overwritten"},
+		},
+		{
+			name: "absolute symlink",
+			entries: []tarEntry{
+				{name: "abs", typeflag: tar.TypeSymlink, linkname: outside},
+				{name: "abs/planted", typeflag: tar.TypeReg, content: "x"},
+			},
+			missing: []string{filepath.Join(outside, "planted")},
+		},
+		{
+			name: "relative symlink climbing out",
+			entries: []tarEntry{
+				{name: "usr/up", typeflag: tar.TypeSymlink, linkname: "../../../outside"},
+				{name: "usr/up/planted", typeflag: tar.TypeReg, content: "x"},
+			},
+			missing: []string{filepath.Join(outside, "planted")},
+		},
+		{
+			name: "symlink under symlink",
+			entries: []tarEntry{
+				{name: "a/", typeflag: tar.TypeDir},
+				{name: "a/b", typeflag: tar.TypeSymlink, linkname: "/"},
+				{name: "a/b/c", typeflag: tar.TypeSymlink, linkname: "/etc"},
+			},
+			missing: []string{"c", "a/c"},
+		},
+		{
+			name: "symlink inside",
+			entries: []tarEntry{
+				{name: "lib/real", typeflag: tar.TypeReg, content: "lib"},
+				{name: "lib/alias", typeflag: tar.TypeSymlink, linkname: "/lib/real"},
+			},
+			files: map[string]string{"lib/alias": "lib"},
+		},
+		{
+			name: "hardlink outside",
+			entries: []tarEntry{
+				{name: "hl", typeflag: tar.TypeLink, linkname: "../outside/secret"},
+				{name: "hl-abs", typeflag: tar.TypeLink, linkname: secret},
+			},
+			missing: []string{"hl", "hl-abs"},
+		},
+		{
+			name: "hardlink through symlink",
+			entries: []tarEntry{
+				{name: "out", typeflag: tar.TypeSymlink, linkname: outside},
+				{name: "hl-sym", typeflag: tar.TypeLink, linkname: "out/secret"},
+			},
+			missing: []string{"hl-sym"},
+		},
+		{
+			name: "hardlink inside",
+			entries: []tarEntry{
+				{name: "bin/sh", typeflag: tar.TypeReg, content: "sh"},
+				{name: "bin/bash", typeflag: tar.TypeLink, linkname: "/bin/sh"},
+			},
+			files: map[string]string{"bin/bash": "sh"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Each archive is extracted next to the outside directory.
+			dir, err := os.MkdirTemp(base, "rootfs-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := extractTar(buildTar(t, tt.entries), dir); err != nil {
+				t.Fatalf("extractTar failed: %v", err)
+			}
+			for name, want := range tt.files {
+				got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+				if err != nil {
+					t.Errorf("reading %s: %v", name, err)
+				} else if string(got) != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			for _, name := range tt.missing {
+				if !filepath.IsAbs(name) {
+					name = filepath.Join(dir, name)
+				}
+				if _, err := os.Lstat(name); !os.IsNotExist(err) {
+					t.Errorf("%s exists, want it missing", name)
+				}
+			}
+			if got, err := os.ReadFile(secret); err != nil || string(got) != "secret" {
+				t.Errorf("outside file changed: %q, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestSymlinkTarget(t *testing.T) {
+	tests := []struct {
+		name, target string
+		want         string
+	}{
+		{"lib/alias", "real", "real"},
+		{"lib/alias", "/lib/real", "real"},
+		{"usr/bin/python", "/usr/lib/python3", "../lib/python3"},
+		{"etc/passwd", "/", ".."},
+		{"abs", "/etc/shadow", "etc/shadow"},
+		{"a/b/up", "../../../../outside", "../../outside"},
+	}
+	for _, tt := range tests {
+		if got := symlinkTarget(tt.name, tt.target); got != tt.want {
+			t.Errorf("symlinkTarget(%q, %q) = %q, want %q", tt.name, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package trivy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var DBStatusTool = mcp.NewTool("trivy_db_status",
+	mcp.WithDescription("Report the version and age of the trivy vulnerability DB in the local cache, or of the trivy server"),
+	output.StoreOption(),
+)
+
+// versionInfo is the output of trivy version --format json.
+type versionInfo struct {
+	Version         string  `json:"Version"`
+	VulnerabilityDB *dbMeta `json:"VulnerabilityDB,omitempty"`
+	JavaDB          *dbMeta `json:"JavaDB,omitempty"`
+}
+
+// dbMeta is the metadata of a trivy DB.
+type dbMeta struct {
+	Version      int       `json:"Version"`
+	NextUpdate   time.Time `json:"NextUpdate"`
+	UpdatedAt    time.Time `json:"UpdatedAt"`
+	DownloadedAt time.Time `json:"DownloadedAt"`
+}
+
+// DBStatus describes the state of a trivy DB in the cache.
+type DBStatus struct {
+	Present      bool       `json:"present"`
+	Version      int        `json:"version,omitempty"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
+	DownloadedAt *time.Time `json:"downloadedAt,omitempty"`
+	NextUpdate   *time.Time `json:"nextUpdate,omitempty"`
+	Age          string     `json:"age,omitempty"`
+	Stale        bool       `json:"stale"`
+}
+
+// Status is the result of the DB status tool.
+type Status struct {
+	TrivyVersion    string   `json:"trivyVersion"`
+	CacheDir        string   `json:"cacheDir,omitempty"`
+	SkipDBUpdate    bool     `json:"skipDBUpdate"`
+	OfflineScan     bool     `json:"offlineScan"`
+	DBRepository    string   `json:"dbRepository,omitempty"`
+	Server          string   `json:"server,omitempty"`
+	VulnerabilityDB DBStatus `json:"vulnerabilityDB"`
+	JavaDB          DBStatus `json:"javaDB"`
+}
+
+func dbStatus(meta *dbMeta, now time.Time) DBStatus {
+	if meta == nil || meta.UpdatedAt.IsZero() {
+		return DBStatus{}
+	}
+	return DBStatus{
+		Present:      true,
+		Version:      meta.Version,
+		UpdatedAt:    &meta.UpdatedAt,
+		DownloadedAt: &meta.DownloadedAt,
+		NextUpdate:   &meta.NextUpdate,
+		Age:          now.Sub(meta.UpdatedAt).Round(time.Minute).String(),
+		Stale:        !meta.NextUpdate.IsZero() && now.After(meta.NextUpdate),
+	}
+}
+
+// defaultTokenHeader is the header trivy sends the token in by default.
+const defaultTokenHeader = "Trivy-Token"
+
+// readVersion returns the version of trivy and of its DBs: those of the
+// server in client mode, or those in the configured cache directory.
+func readVersion(ctx context.Context) (versionInfo, error) {
+	var info versionInfo
+	if config.Server != "" {
+		return info, readServerVersion(ctx, &info)
+	}
+
+	args := []string{"version", "--format", "json"}
+	if config.CacheDir != "" {
+		args = append(args, "--cache-dir", config.CacheDir)
+	}
+
+	var stderr bytes.Buffer
+	versionCmd := exec.CommandContext(ctx, "trivy", args...)
+	versionCmd.Stderr = &stderr
+	outBytes, err := versionCmd.Output()
+	if err != nil {
+		return info, fmt.Errorf("failed to read trivy DB status: %w \n %s", err, stderr.String())
+	}
+	if err := json.Unmarshal(outBytes, &info); err != nil {
+		return info, fmt.Errorf("failed to parse trivy version: %w", err)
+	}
+	return info, nil
+}
+
+// readServerVersion reads the version of the trivy server, which serves
+// the same document as trivy version --format json.
+func readServerVersion(ctx context.Context, info *versionInfo) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(config.Server, "/")+"/version", nil)
+	if err != nil {
+		return fmt.Errorf("failed to read trivy server version: %w", err)
+	}
+	if config.Token != "" {
+		header := config.TokenHeader
+		if header == "" {
+			header = defaultTokenHeader
+		}
+		req.Header.Set(header, config.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to read trivy server version: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to read trivy server version: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(info); err != nil {
+		return fmt.Errorf("failed to parse trivy server version: %w", err)
+	}
+	return nil
+}
+
+// DBStatusHandler is the handler function that handles DB status requests,
+// reading the metadata of the DBs in the configured cache directory, or
+// of the trivy server in client mode.
+func DBStatusHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	info, err := readVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	status := Status{
+		TrivyVersion:    info.Version,
+		CacheDir:        config.CacheDir,
+		SkipDBUpdate:    config.SkipDBUpdate,
+		OfflineScan:     config.OfflineScan,
+		DBRepository:    config.DBRepository,
+		Server:          config.Server,
+		VulnerabilityDB: dbStatus(info.VulnerabilityDB, now),
+		JavaDB:          dbStatus(info.JavaDB, now),
+	}
+	statusBytes, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode trivy DB status: %w", err)
+	}
+	return mcp.NewToolResultText(string(statusBytes)), nil
+}
+
+// WithDBStatusTool adds the DB status tool to the given mcp server
+func WithDBStatusTool(srv *server.MCPServer) *server.MCPServer {
+	srv.AddTool(DBStatusTool, params.Validate(DBStatusTool, DBStatusHandler))
+	return srv
+}
@@ -0,0 +1,182 @@
+package trivy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the trivy_image_diff tool, which scans two
+// images and reports how the vulnerabilities changed between them, e.g.
+// when bumping a base image.
+
+var ImageDiffTool = mcp.NewTool("trivy_image_diff",
+	mcp.WithDescription("Compare the vulnerabilities of two images, reporting the fixed, introduced and unchanged ones"),
+	mcp.WithString("base",
+		mcp.Required(),
+		mcp.Description("The image to compare from, e.g. the current base image"),
+		params.Format(params.ImageFormat),
+	),
+	mcp.WithString("target",
+		mcp.Required(),
+		mcp.Description("The image to compare to, e.g. the upgraded base image"),
+		params.Format(params.ImageFormat),
+	),
+	severityOption(),
+	ignoreUnfixedOption(),
+	pkgTypesOption(),
+	refreshOption(),
+	mcp.WithBoolean("list-unchanged",
+		mcp.Description("List the unchanged vulnerabilities instead of only counting them"),
+		mcp.DefaultBool(false),
+	),
+	output.StoreOption(),
+)
+
+// Delta is the difference between the vulnerabilities of two images,
+// where the findings are grouped by severity.
+type Delta struct {
+	Base       string               `json:"base"`
+	Target     string               `json:"target"`
+	Counts     DeltaCounts          `json:"counts"`
+	Fixed      map[string][]Finding `json:"fixed"`
+	Introduced map[string][]Finding `json:"introduced"`
+	Unchanged  map[string][]Finding `json:"unchanged,omitempty"`
+}
+
+// DeltaCounts holds the number of findings per severity of each group.
+type DeltaCounts struct {
+	Fixed      map[string]int `json:"fixed"`
+	Introduced map[string]int `json:"introduced"`
+	Unchanged  map[string]int `json:"unchanged"`
+}
+
+// findingKey identifies a vulnerability of a package regardless of the
+// installed version, so an upgrade which doesn't fix it is unchanged.
+type findingKey struct {
+	id  string
+	pkg string
+}
+
+func keyOf(finding Finding) findingKey {
+	return findingKey{id: finding.ID, pkg: finding.Package}
+}
+
+// DiffReports compares the vulnerabilities of the base and target reports.
+func DiffReports(base, target *Report, listUnchanged bool) Delta {
+	delta := Delta{
+		Base:   base.ArtifactName,
+		Target: target.ArtifactName,
+		Counts: DeltaCounts{
+			Fixed:      severityCounts(),
+			Introduced: severityCounts(),
+			Unchanged:  severityCounts(),
+		},
+		Fixed:      map[string][]Finding{},
+		Introduced: map[string][]Finding{},
+	}
+	if listUnchanged {
+		delta.Unchanged = map[string][]Finding{}
+	}
+
+	baseKeys := make(map[findingKey]bool)
+	for _, finding := range base.Vulnerabilities() {
+		baseKeys[keyOf(finding)] = true
+	}
+
+	targetKeys := make(map[findingKey]bool)
+	for _, finding := range target.Vulnerabilities() {
+		key := keyOf(finding)
+		if targetKeys[key] {
+			continue
+		}
+		targetKeys[key] = true
+		if baseKeys[key] {
+			delta.Counts.Unchanged[finding.Severity]++
+			if listUnchanged {
+				delta.Unchanged[finding.Severity] = append(delta.Unchanged[finding.Severity], finding)
+			}
+			continue
+		}
+		delta.Counts.Introduced[finding.Severity]++
+		delta.Introduced[finding.Severity] = append(delta.Introduced[finding.Severity], finding)
+	}
+
+	fixedKeys := make(map[findingKey]bool)
+	for _, finding := range base.Vulnerabilities() {
+		key := keyOf(finding)
+		if targetKeys[key] || fixedKeys[key] {
+			continue
+		}
+		fixedKeys[key] = true
+		delta.Counts.Fixed[finding.Severity]++
+		delta.Fixed[finding.Severity] = append(delta.Fixed[finding.Severity], finding)
+	}
+
+	for _, group := range []map[string][]Finding{delta.Fixed, delta.Introduced, delta.Unchanged} {
+		for _, findings := range group {
+			SortFindings(findings)
+		}
+	}
+	return delta
+}
+
+// ImageDiffHandler is the handler function that handles image diff
+// requests, scanning both images and comparing their vulnerabilities.
+func ImageDiffHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	baseImage, err := params.String(req, "base")
+	if err != nil {
+		return nil, err
+	}
+	targetImage, err := params.String(req, "target")
+	if err != nil {
+		return nil, err
+	}
+	var opts ScanOptions
+	if opts.Severities, err = params.StringSlice(req, "severity"); err != nil {
+		return nil, err
+	}
+	if opts.IgnoreUnfixed, err = params.Bool(req, "ignore-unfixed", false); err != nil {
+		return nil, err
+	}
+	if opts.PkgTypes, err = params.StringSlice(req, "pkg-types"); err != nil {
+		return nil, err
+	}
+	if opts.Refresh, err = params.Bool(req, "refresh", false); err != nil {
+		return nil, err
+	}
+	listUnchanged, err := params.Bool(req, "list-unchanged", false)
+	if err != nil {
+		return nil, err
+	}
+	opts.Scanners = []string{"vuln"}
+
+	// The images are scanned one after the other, as concurrent trivy
+	// processes would contend for the lock of the vulnerability DB.
+	baseReport, _, err := ScanReport(ctx, "image", baseImage, opts)
+	if err != nil {
+		return nil, err
+	}
+	targetReport, _, err := ScanReport(ctx, "image", targetImage, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := DiffReports(baseReport, targetReport, listUnchanged)
+	deltaBytes, err := json.MarshalIndent(delta, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode vulnerability delta: %w", err)
+	}
+	return mcp.NewToolResultText(string(deltaBytes)), nil
+}
+
+// WithImageDiffTool adds the image diff tool to the given mcp server
+func WithImageDiffTool(srv *server.MCPServer) *server.MCPServer {
+	srv.AddTool(ImageDiffTool, params.Validate(ImageDiffTool, ImageDiffHandler))
+	return srv
+}
@@ -0,0 +1,136 @@
+package trivy
+
+import (
+	"reflect"
+	"testing"
+)
+
+// vulnReport returns a report of the given vulnerabilities, in a single
+// result.
+func vulnReport(name string, vulns ...Vulnerability) *Report {
+	return &Report{ArtifactName: name, Results: []Result{{Target: name, Vulnerabilities: vulns}}}
+}
+
+// findingIDs returns the "ID package" pairs of the findings, by severity.
+func findingIDs(group map[string][]Finding) map[string][]string {
+	if group == nil {
+		return nil
+	}
+	ids := make(map[string][]string, len(group))
+	for severity, findings := range group {
+		for _, finding := range findings {
+			ids[severity] = append(ids[severity], finding.ID+" "+finding.Package)
+		}
+	}
+	return ids
+}
+
+// checkCounts checks the counts of a group of the delta match the findings
+// listed in it.
+func checkCounts(t *testing.T, group string, counts map[string]int, listed map[string][]string) {
+	t.Helper()
+	for _, severity := range Severities {
+		if counts[severity] != len(listed[severity]) {
+			t.Errorf("%s count of %s = %d, want %d", group, severity, counts[severity], len(listed[severity]))
+		}
+	}
+}
+
+func TestDiffReports(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       []Vulnerability
+		target     []Vulnerability
+		fixed      map[string][]string
+		introduced map[string][]string
+		unchanged  map[string][]string
+	}{
+		{
+			name:       "fixed and introduced",
+			base:       []Vulnerability{{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "HIGH"}},
+			target:     []Vulnerability{{VulnerabilityID: "CVE-2", PkgName: "zlib", InstalledVersion: "1.2.13", Severity: "LOW"}},
+			fixed:      map[string][]string{"HIGH": {"CVE-1 openssl"}},
+			introduced: map[string][]string{"LOW": {"CVE-2 zlib"}},
+			unchanged:  map[string][]string{},
+		},
+		{
+			name:       "upgrade not fixing",
+			base:       []Vulnerability{{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "HIGH"}},
+			target:     []Vulnerability{{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.5", Severity: "HIGH"}},
+			fixed:      map[string][]string{},
+			introduced: map[string][]string{},
+			unchanged:  map[string][]string{"HIGH": {"CVE-1 openssl"}},
+		},
+		{
+			name:       "moved between severities",
+			base:       []Vulnerability{{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "MEDIUM"}},
+			target:     []Vulnerability{{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "CRITICAL"}},
+			fixed:      map[string][]string{},
+			introduced: map[string][]string{},
+			unchanged:  map[string][]string{"CRITICAL": {"CVE-1 openssl"}},
+		},
+		{
+			name:       "moved between packages",
+			base:       []Vulnerability{{VulnerabilityID: "CVE-1", PkgName: "libssl3", InstalledVersion: "3.0.2", Severity: "HIGH"}},
+			target:     []Vulnerability{{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "HIGH"}},
+			fixed:      map[string][]string{"HIGH": {"CVE-1 libssl3"}},
+			introduced: map[string][]string{"HIGH": {"CVE-1 openssl"}},
+			unchanged:  map[string][]string{},
+		},
+		{
+			name: "same vulnerability in several packages",
+			base: []Vulnerability{
+				{VulnerabilityID: "CVE-1", PkgName: "libssl3", InstalledVersion: "3.0.2", Severity: "HIGH"},
+				{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "HIGH"},
+			},
+			target: []Vulnerability{
+				{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "HIGH"},
+			},
+			fixed:      map[string][]string{"HIGH": {"CVE-1 libssl3"}},
+			introduced: map[string][]string{},
+			unchanged:  map[string][]string{"HIGH": {"CVE-1 openssl"}},
+		},
+		{
+			name: "duplicates counted once",
+			base: []Vulnerability{
+				{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "HIGH"},
+				{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "HIGH"},
+			},
+			target: []Vulnerability{
+				{VulnerabilityID: "CVE-2", PkgName: "zlib", InstalledVersion: "1.2.13", Severity: "LOW"},
+				{VulnerabilityID: "CVE-2", PkgName: "zlib", InstalledVersion: "1.2.13", Severity: "LOW"},
+			},
+			fixed:      map[string][]string{"HIGH": {"CVE-1 openssl"}},
+			introduced: map[string][]string{"LOW": {"CVE-2 zlib"}},
+			unchanged:  map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := DiffReports(vulnReport("base", tt.base...), vulnReport("target", tt.target...), true)
+			if got := findingIDs(delta.Fixed); !reflect.DeepEqual(got, tt.fixed) {
+				t.Errorf("fixed = %v, want %v", got, tt.fixed)
+			}
+			if got := findingIDs(delta.Introduced); !reflect.DeepEqual(got, tt.introduced) {
+				t.Errorf("introduced = %v, want %v", got, tt.introduced)
+			}
+			if got := findingIDs(delta.Unchanged); !reflect.DeepEqual(got, tt.unchanged) {
+				t.Errorf("unchanged = %v, want %v", got, tt.unchanged)
+			}
+			checkCounts(t, "fixed", delta.Counts.Fixed, tt.fixed)
+			checkCounts(t, "introduced", delta.Counts.Introduced, tt.introduced)
+			checkCounts(t, "unchanged", delta.Counts.Unchanged, tt.unchanged)
+		})
+	}
+}
+
+func TestDiffReportsUnchangedNotListed(t *testing.T) {
+	vuln := Vulnerability{VulnerabilityID: "CVE-1", PkgName: "openssl", InstalledVersion: "3.0.2", Severity: "HIGH"}
+	delta := DiffReports(vulnReport("base", vuln), vulnReport("target", vuln), false)
+	if delta.Unchanged != nil {
+		t.Errorf("unchanged findings listed: %v", delta.Unchanged)
+	}
+	if delta.Counts.Unchanged["HIGH"] != 1 {
+		t.Errorf("unchanged count of HIGH = %d, want 1", delta.Counts.Unchanged["HIGH"])
+	}
+}
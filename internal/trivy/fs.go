@@ -0,0 +1,96 @@
+package trivy
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the trivy tools which scan the local filesystem:
+// a project directory or lockfile, configuration files and a root
+// filesystem of an unpacked container image.
+
+var FSTool = mcp.NewTool("trivy_fs",
+	mcp.WithDescription("Scan a local directory or lockfile for vulnerabilities and secrets"),
+	mcp.WithString("path",
+		mcp.Required(),
+		mcp.Description("The path of the directory or file to scan"),
+	),
+	severityOption(),
+	ignoreUnfixedOption(),
+	scannersOption(),
+	pkgTypesOption(),
+	formatOption("table", "json", "sarif", "cyclonedx"),
+	topOption(),
+	fullReportOption(),
+	sarifOutputOption(),
+	sarifResourceOption(),
+	output.StoreOption(),
+)
+
+var ConfigTool = mcp.NewTool("trivy_config",
+	mcp.WithDescription("Scan Dockerfiles, compose files, Kubernetes manifests and Terraform for misconfigurations"),
+	mcp.WithString("path",
+		mcp.Required(),
+		mcp.Description("The path of the directory or file to scan"),
+	),
+	severityOption(),
+	formatOption("table", "json", "sarif"),
+	topOption(),
+	fullReportOption(),
+	sarifOutputOption(),
+	sarifResourceOption(),
+	output.StoreOption(),
+)
+
+var RootFSTool = mcp.NewTool("trivy_rootfs",
+	mcp.WithDescription("Scan a root filesystem, such as an unpacked container image, for vulnerabilities"),
+	mcp.WithString("path",
+		mcp.Required(),
+		mcp.Description("The path of the root filesystem to scan"),
+	),
+	severityOption(),
+	ignoreUnfixedOption(),
+	scannersOption(),
+	pkgTypesOption(),
+	formatOption("table", "json", "sarif", "cyclonedx"),
+	topOption(),
+	fullReportOption(),
+	sarifOutputOption(),
+	sarifResourceOption(),
+	output.StoreOption(),
+)
+
+var (
+	fsScan     = scanTool{command: "fs", targetArg: "path", vulnScan: true}
+	configScan = scanTool{command: "config", targetArg: "path"}
+	rootFSScan = scanTool{command: "rootfs", targetArg: "path", vulnScan: true}
+)
+
+// FSHandler is the handler function that handles filesystem scan requests.
+func FSHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return fsScan.handler()(ctx, req)
+}
+
+// ConfigHandler is the handler function that handles misconfiguration
+// scan requests.
+func ConfigHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return configScan.handler()(ctx, req)
+}
+
+// RootFSHandler is the handler function that handles root filesystem
+// scan requests.
+func RootFSHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return rootFSScan.handler()(ctx, req)
+}
+
+// WithFSTools adds the filesystem, config and rootfs tools to the given mcp server
+func WithFSTools(srv *server.MCPServer) *server.MCPServer {
+	srv.AddTool(FSTool, params.Validate(FSTool, FSHandler))
+	srv.AddTool(ConfigTool, params.Validate(ConfigTool, ConfigHandler))
+	srv.AddTool(RootFSTool, params.Validate(RootFSTool, RootFSHandler))
+	return srv
+}
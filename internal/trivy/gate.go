@@ -0,0 +1,261 @@
+package trivy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the trivy_gate tool, which evaluates the scan of
+// an image against a policy and returns a pass or fail verdict, so agents
+// can refuse to commit or push images violating the policy.
+
+var GateTool = mcp.NewTool("trivy_gate",
+	mcp.WithDescription("Evaluate the vulnerabilities of an image against a policy and return a pass or fail verdict"),
+	mcp.WithString("image",
+		mcp.Required(),
+		mcp.Description("The name of the image to evaluate"),
+		params.Format(params.ImageFormat),
+	),
+	mcp.WithNumber("max-critical",
+		mcp.Description("The maximum number of critical vulnerabilities allowed, -1 for no limit"),
+		mcp.DefaultNumber(0),
+		mcp.Min(-1),
+	),
+	mcp.WithNumber("max-high",
+		mcp.Description("The maximum number of high vulnerabilities allowed, -1 for no limit"),
+		mcp.DefaultNumber(-1),
+		mcp.Min(-1),
+	),
+	mcp.WithArray("banned-packages",
+		mcp.Description("Names of packages which must not be present in the image"),
+		params.StringArray(),
+	),
+	mcp.WithArray("ignore",
+		mcp.Description("Vulnerability IDs to ignore, optionally expiring, e.g. \"CVE-2023-1234 exp:2025-12-31\""),
+		params.StringArray(),
+	),
+	mcp.WithString("ignorefile",
+		mcp.Description("The path of a .trivyignore file, relative to the workspace, listing vulnerability IDs to ignore"),
+	),
+	ignoreUnfixedOption(),
+	refreshOption(),
+	output.StoreOption(),
+)
+
+// Verdict is the outcome of evaluating a scan against a policy.
+type Verdict struct {
+	Image          string         `json:"image"`
+	Passed         bool           `json:"passed"`
+	Verdict        string         `json:"verdict"`
+	Reasons        []string       `json:"reasons"`
+	Counts         map[string]int `json:"counts"`
+	Ignored        []string       `json:"ignored,omitempty"`
+	ExpiredIgnores []string       `json:"expiredIgnores,omitempty"`
+}
+
+// Policy is the set of rules a scan is evaluated against.
+type Policy struct {
+	MaxCritical    int
+	MaxHigh        int
+	BannedPackages []string
+	Ignores        []IgnoreEntry
+}
+
+// IgnoreEntry is a vulnerability ID to ignore, until the optional expiry
+// date, using the .trivyignore syntax.
+type IgnoreEntry struct {
+	ID      string
+	Expires time.Time
+}
+
+// ParseIgnoreEntries parses entries in the .trivyignore syntax, where each
+// line holds an ID optionally followed by exp:YYYY-MM-DD, and lines
+// starting with # are comments.
+func ParseIgnoreEntries(r io.Reader) ([]IgnoreEntry, error) {
+	var entries []IgnoreEntry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		entry := IgnoreEntry{ID: fields[0]}
+		for _, field := range fields[1:] {
+			date, found := strings.CutPrefix(field, "exp:")
+			if !found {
+				continue
+			}
+			expires, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid expiry date of %s: %q", params.ErrInvalidParams, entry.ID, date)
+			}
+			entry.Expires = expires
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Evaluate applies the policy to the report at the given time.
+func (p Policy) Evaluate(report *Report, now time.Time) Verdict {
+	verdict := Verdict{
+		Image:   report.ArtifactName,
+		Reasons: []string{},
+		Counts:  severityCounts(),
+	}
+
+	ignored := make(map[string]bool)
+	for _, entry := range p.Ignores {
+		// Entries expire at the end of their expiry date.
+		if !entry.Expires.IsZero() && now.After(entry.Expires.AddDate(0, 0, 1)) {
+			verdict.ExpiredIgnores = append(verdict.ExpiredIgnores,
+				fmt.Sprintf("%s expired on %s", entry.ID, entry.Expires.Format("2006-01-02")))
+			continue
+		}
+		ignored[entry.ID] = true
+	}
+
+	banned := make(map[string]bool, len(p.BannedPackages))
+	for _, pkg := range p.BannedPackages {
+		banned[pkg] = true
+	}
+
+	bannedFound := make(map[string]bool)
+	for _, result := range report.Results {
+		for _, pkg := range result.Packages {
+			if banned[pkg.Name] {
+				bannedFound[pkg.Name+" "+pkg.Version] = true
+			}
+		}
+	}
+
+	ignoredFound := make(map[string]bool)
+	for _, finding := range report.Vulnerabilities() {
+		if ignored[finding.ID] {
+			ignoredFound[finding.ID] = true
+			continue
+		}
+		verdict.Counts[finding.Severity]++
+		// Vulnerable packages are listed even without --list-all-pkgs.
+		if banned[finding.Package] {
+			bannedFound[finding.Package+" "+finding.InstalledVersion] = true
+		}
+	}
+
+	if p.MaxCritical >= 0 && verdict.Counts["CRITICAL"] > p.MaxCritical {
+		verdict.Reasons = append(verdict.Reasons,
+			fmt.Sprintf("%d critical vulnerabilities exceed the maximum of %d", verdict.Counts["CRITICAL"], p.MaxCritical))
+	}
+	if p.MaxHigh >= 0 && verdict.Counts["HIGH"] > p.MaxHigh {
+		verdict.Reasons = append(verdict.Reasons,
+			fmt.Sprintf("%d high vulnerabilities exceed the maximum of %d", verdict.Counts["HIGH"], p.MaxHigh))
+	}
+	for _, pkg := range sortedKeys(bannedFound) {
+		verdict.Reasons = append(verdict.Reasons, fmt.Sprintf("banned package %s is present", pkg))
+	}
+	verdict.Ignored = sortedKeys(ignoredFound)
+
+	verdict.Passed = len(verdict.Reasons) == 0
+	verdict.Verdict = "fail"
+	if verdict.Passed {
+		verdict.Verdict = "pass"
+	}
+	return verdict
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GateHandler is the handler function that handles gate requests, scanning
+// the image and evaluating the findings against the requested policy.
+func GateHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	image, err := params.String(req, "image")
+	if err != nil {
+		return nil, err
+	}
+	var policy Policy
+	if policy.MaxCritical, err = params.Int(req, "max-critical", 0); err != nil {
+		return nil, err
+	}
+	if policy.MaxHigh, err = params.Int(req, "max-high", -1); err != nil {
+		return nil, err
+	}
+	if policy.BannedPackages, err = params.StringSlice(req, "banned-packages"); err != nil {
+		return nil, err
+	}
+	ignores, err := params.StringSlice(req, "ignore")
+	if err != nil {
+		return nil, err
+	}
+	ignorefile, err := params.OptionalString(req, "ignorefile", "")
+	if err != nil {
+		return nil, err
+	}
+	ignoreUnfixed, err := params.Bool(req, "ignore-unfixed", false)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := params.Bool(req, "refresh", false)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.Ignores, err = ParseIgnoreEntries(strings.NewReader(strings.Join(ignores, "\n"))); err != nil {
+		return nil, err
+	}
+	if ignorefile != "" {
+		data, err := workspace.ReadFile(ignorefile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open ignore file: %w", err)
+		}
+		entries, err := ParseIgnoreEntries(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ignore file %s: %w", ignorefile, err)
+		}
+		policy.Ignores = append(policy.Ignores, entries...)
+	}
+
+	opts := ScanOptions{
+		IgnoreUnfixed:   ignoreUnfixed,
+		Scanners:        []string{"vuln"},
+		ListAllPackages: len(policy.BannedPackages) > 0,
+		Refresh:         refresh,
+	}
+	report, _, err := ScanReport(ctx, "image", image, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := policy.Evaluate(report, time.Now())
+	verdictBytes, err := json.MarshalIndent(verdict, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode gate verdict: %w", err)
+	}
+	return mcp.NewToolResultText(string(verdictBytes)), nil
+}
+
+// WithGateTool adds the gate tool to the given mcp server
+func WithGateTool(srv *server.MCPServer) *server.MCPServer {
+	srv.AddTool(GateTool, params.Validate(GateTool, GateHandler))
+	return srv
+}
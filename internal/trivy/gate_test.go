@@ -0,0 +1,181 @@
+package trivy
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+)
+
+func TestParseIgnoreEntries(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []IgnoreEntry
+	}{
+		{"empty", "", nil},
+		{"comments and blank lines", "# accepted risks\n\n   \n", nil},
+		{"IDs", "CVE-2023-1234\n  GHSA-xxxx-yyyy-zzzz  \n", []IgnoreEntry{
+			{ID: "CVE-2023-1234"},
+			{ID: "GHSA-xxxx-yyyy-zzzz"},
+		}},
+		{"expiry", "CVE-2023-1234 exp:2025-12-31\n", []IgnoreEntry{
+			{ID: "CVE-2023-1234", Expires: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		}},
+		{"other fields", "CVE-2023-1234 # not exploitable exp:2025-06-30\n", []IgnoreEntry{
+			{ID: "CVE-2023-1234", Expires: time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIgnoreEntries(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseIgnoreEntries failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIgnoreEntries = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIgnoreEntriesInvalid(t *testing.T) {
+	for _, input := range []string{"CVE-2023-1234 exp:2025-13-01", "CVE-2023-1234 exp:31/12/2025", "CVE-2023-1234 exp:"} {
+		if _, err := ParseIgnoreEntries(strings.NewReader(input)); !errors.Is(err, params.ErrInvalidParams) {
+			t.Errorf("ParseIgnoreEntries(%q) = %v, want an invalid params error", input, err)
+		}
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	report := &Report{
+		ArtifactName: "app:1.0",
+		Results: []Result{
+			{
+				Target:   "app:1.0 (debian 12.5)",
+				Packages: []Package{{Name: "openssl", Version: "3.0.11"}, {Name: "telnetd", Version: "0.17"}},
+				Vulnerabilities: []Vulnerability{
+					{VulnerabilityID: "CVE-2024-0001", PkgName: "openssl", InstalledVersion: "3.0.11", Severity: "CRITICAL"},
+					{VulnerabilityID: "CVE-2024-0002", PkgName: "openssl", InstalledVersion: "3.0.11", Severity: "HIGH"},
+					{VulnerabilityID: "CVE-2024-0003", PkgName: "zlib", InstalledVersion: "1.2.13", Severity: "HIGH"},
+					{VulnerabilityID: "CVE-2024-0004", PkgName: "zlib", InstalledVersion: "1.2.13", Severity: "LOW"},
+				},
+			},
+			{
+				Target: "usr/local/bin/app",
+				Vulnerabilities: []Vulnerability{
+					{VulnerabilityID: "CVE-2024-0005", PkgName: "golang.org/x/net", InstalledVersion: "v0.17.0", Severity: "MEDIUM"},
+				},
+			},
+		},
+	}
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	counts := func(critical, high, medium, low int) map[string]int {
+		c := severityCounts()
+		c["CRITICAL"], c["HIGH"], c["MEDIUM"], c["LOW"] = critical, high, medium, low
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   Verdict
+	}{
+		{
+			name:   "defaults",
+			policy: Policy{MaxCritical: 0, MaxHigh: -1},
+			want: Verdict{
+				Reasons: []string{"1 critical vulnerabilities exceed the maximum of 0"},
+				Counts:  counts(1, 2, 1, 1),
+			},
+		},
+		{
+			name:   "unlimited",
+			policy: Policy{MaxCritical: -1, MaxHigh: -1},
+			want:   Verdict{Passed: true, Reasons: []string{}, Counts: counts(1, 2, 1, 1)},
+		},
+		{
+			name:   "within the maximums",
+			policy: Policy{MaxCritical: 1, MaxHigh: 2},
+			want:   Verdict{Passed: true, Reasons: []string{}, Counts: counts(1, 2, 1, 1)},
+		},
+		{
+			name:   "above the maximums",
+			policy: Policy{MaxCritical: 0, MaxHigh: 1},
+			want: Verdict{
+				Reasons: []string{
+					"1 critical vulnerabilities exceed the maximum of 0",
+					"2 high vulnerabilities exceed the maximum of 1",
+				},
+				Counts: counts(1, 2, 1, 1),
+			},
+		},
+		{
+			name:   "banned packages",
+			policy: Policy{MaxCritical: -1, MaxHigh: -1, BannedPackages: []string{"telnetd", "zlib", "curl"}},
+			want: Verdict{
+				Reasons: []string{
+					"banned package telnetd 0.17 is present",
+					"banned package zlib 1.2.13 is present",
+				},
+				Counts: counts(1, 2, 1, 1),
+			},
+		},
+		{
+			name: "ignored",
+			policy: Policy{MaxCritical: 0, MaxHigh: 1, Ignores: []IgnoreEntry{
+				{ID: "CVE-2024-0001"},
+				{ID: "CVE-2024-0003", Expires: time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+				{ID: "CVE-2024-9999"},
+			}},
+			want: Verdict{
+				Passed:  true,
+				Reasons: []string{},
+				Counts:  counts(0, 1, 1, 1),
+				Ignored: []string{"CVE-2024-0001", "CVE-2024-0003"},
+			},
+		},
+		{
+			name: "expired ignore",
+			policy: Policy{MaxCritical: 0, MaxHigh: -1, Ignores: []IgnoreEntry{
+				{ID: "CVE-2024-0001", Expires: time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC)},
+			}},
+			want: Verdict{
+				Reasons:        []string{"1 critical vulnerabilities exceed the maximum of 0"},
+				Counts:         counts(1, 2, 1, 1),
+				ExpiredIgnores: []string{"CVE-2024-0001 expired on 2025-06-14"},
+			},
+		},
+		{
+			name: "ignored banned package",
+			policy: Policy{MaxCritical: 0, MaxHigh: -1, BannedPackages: []string{"openssl"}, Ignores: []IgnoreEntry{
+				{ID: "CVE-2024-0001"},
+			}},
+			want: Verdict{
+				Reasons: []string{"banned package openssl 3.0.11 is present"},
+				Counts:  counts(0, 2, 1, 1),
+				Ignored: []string{"CVE-2024-0001"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Image = "app:1.0"
+			tt.want.Verdict = "fail"
+			if tt.want.Passed {
+				tt.want.Verdict = "pass"
+			}
+			got := tt.policy.Evaluate(report, now)
+			// Evaluate returns an empty slice when nothing was ignored.
+			if tt.want.Ignored == nil {
+				tt.want.Ignored = []string{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
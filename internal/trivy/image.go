@@ -1,11 +1,10 @@
 package trivy
 
 import (
-	"bytes"
 	"context"
-	"fmt"
-	"os/exec"
 
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -15,29 +14,31 @@ var ImageTool = mcp.NewTool("trivy_image",
 	mcp.WithString("image",
 		mcp.Required(),
 		mcp.Description("The name of the image to scan"),
+		params.Format(params.ImageFormat),
 	),
+	severityOption(),
+	ignoreUnfixedOption(),
+	scannersOption(),
+	pkgTypesOption(),
+	formatOption("table", "json", "sarif", "cyclonedx"),
+	topOption(),
+	fullReportOption(),
+	sarifOutputOption(),
+	sarifResourceOption(),
+	refreshOption(),
+	output.StoreOption(),
 )
 
+var imageScan = scanTool{command: "image", targetArg: "image", vulnScan: true}
+
 // ImageHandler is the handler function that handles image scan requests
 // and actually makes a use of trivy cli tool to scan the image.
 func ImageHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	image := req.Params.Arguments["image"].(string)
-
-	// Run the trivy command to scan the image
-	var stderr bytes.Buffer
-	scanCmd := exec.Command("trivy", "image", image)
-	scanCmd.Stderr = &stderr
-	outBytes, err := scanCmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to scan image: %w \n %s", err, stderr.String())
-	}
-	result := string(outBytes)
-
-	return mcp.NewToolResultText(fmt.Sprintf("%s", result)), nil
+	return imageScan.handler()(ctx, req)
 }
 
 // WithImageTool adds the image tool to the given mcp server
 func WithImageTool(srv *server.MCPServer) *server.MCPServer {
-	srv.AddTool(ImageTool, ImageHandler)
+	srv.AddTool(ImageTool, params.Validate(ImageTool, ImageHandler))
 	return srv
 }
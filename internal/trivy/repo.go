@@ -0,0 +1,65 @@
+package trivy
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/mark3labs/mcp-docker/internal/output"
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var RepoTool = mcp.NewTool("trivy_repo",
+	mcp.WithDescription("Scan a remote git repository for vulnerabilities and secrets"),
+	mcp.WithString("repository",
+		mcp.Required(),
+		mcp.Description("The URL of the git repository to scan, e.g. https://github.com/org/repo"),
+	),
+	severityOption(),
+	ignoreUnfixedOption(),
+	scannersOption(),
+	pkgTypesOption(),
+	formatOption("table", "json", "sarif", "cyclonedx"),
+	topOption(),
+	fullReportOption(),
+	sarifOutputOption(),
+	sarifResourceOption(),
+	output.StoreOption(),
+)
+
+var repoScan = scanTool{command: "repo", targetArg: "repository", vulnScan: true, prepare: checkRepoURL}
+
+// scpLikeURL matches the scp-like syntax of git URLs, e.g.
+// git@github.com:org/repo.git.
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^/]`)
+
+// checkRepoURL only lets remote git URLs through, since trivy repo scans
+// local paths too, which are for trivy_fs to scan.
+func checkRepoURL(_ context.Context, repository string) (string, func(), error) {
+	if scpLikeURL.MatchString(repository) {
+		return repository, func() {}, nil
+	}
+	u, err := url.Parse(repository)
+	if err == nil && u.Host != "" {
+		switch u.Scheme {
+		case "https", "http", "ssh", "git":
+			return repository, func() {}, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w: %q is not a remote git URL", params.ErrInvalidParams, repository)
+}
+
+// RepoHandler is the handler function that handles git repository scan
+// requests.
+func RepoHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return repoScan.handler()(ctx, req)
+}
+
+// WithRepoTool adds the repository tool to the given mcp server
+func WithRepoTool(srv *server.MCPServer) *server.MCPServer {
+	srv.AddTool(RepoTool, params.Validate(RepoTool, RepoHandler))
+	return srv
+}
@@ -0,0 +1,303 @@
+package trivy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// In this file, we define the model of the JSON report produced by
+// trivy, along with the summary returned to the clients instead of the
+// full, often huge, report.
+
+// Severities lists the trivy severities from the most to the least severe.
+var Severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"}
+
+// Report is the JSON report produced by trivy with --format json.
+type Report struct {
+	SchemaVersion int      `json:"SchemaVersion"`
+	ArtifactName  string   `json:"ArtifactName"`
+	ArtifactType  string   `json:"ArtifactType"`
+	Metadata      Metadata `json:"Metadata"`
+	Results       []Result `json:"Results"`
+	// Cached is set when the report was served from the result cache.
+	Cached bool `json:"-"`
+}
+
+// Metadata describes the scanned artifact.
+type Metadata struct {
+	ImageID     string   `json:"ImageID,omitempty"`
+	DiffIDs     []string `json:"DiffIDs,omitempty"`
+	RepoTags    []string `json:"RepoTags,omitempty"`
+	RepoDigests []string `json:"RepoDigests,omitempty"`
+	OS          *struct {
+		Family string `json:"Family"`
+		Name   string `json:"Name"`
+	} `json:"OS,omitempty"`
+}
+
+// Result holds the findings for a single target of the artifact, such as
+// the OS packages or a language lockfile.
+type Result struct {
+	Target            string             `json:"Target"`
+	Class             string             `json:"Class"`
+	Type              string             `json:"Type,omitempty"`
+	Packages          []Package          `json:"Packages,omitempty"`
+	Vulnerabilities   []Vulnerability    `json:"Vulnerabilities,omitempty"`
+	Misconfigurations []Misconfiguration `json:"Misconfigurations,omitempty"`
+	Secrets           []Secret           `json:"Secrets,omitempty"`
+	Licenses          []License          `json:"Licenses,omitempty"`
+}
+
+// Package is a package found in the target, only listed by trivy when
+// scanning with --list-all-pkgs.
+type Package struct {
+	ID       string   `json:"ID,omitempty"`
+	Name     string   `json:"Name"`
+	Version  string   `json:"Version"`
+	Licenses []string `json:"Licenses,omitempty"`
+	Layer    Layer    `json:"Layer,omitempty"`
+}
+
+// Layer identifies the image layer a finding was introduced in.
+type Layer struct {
+	Digest string `json:"Digest,omitempty"`
+	DiffID string `json:"DiffID,omitempty"`
+}
+
+// Vulnerability is a single vulnerability found in a package.
+type Vulnerability struct {
+	VulnerabilityID  string `json:"VulnerabilityID"`
+	PkgID            string `json:"PkgID,omitempty"`
+	PkgName          string `json:"PkgName"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion,omitempty"`
+	Status           string `json:"Status,omitempty"`
+	Layer            Layer  `json:"Layer,omitempty"`
+	SeveritySource   string `json:"SeveritySource,omitempty"`
+	PrimaryURL       string `json:"PrimaryURL,omitempty"`
+	Title            string `json:"Title,omitempty"`
+	Description      string `json:"Description,omitempty"`
+	Severity         string `json:"Severity"`
+}
+
+// Misconfiguration is a single misconfiguration found in a config file.
+type Misconfiguration struct {
+	Type          string `json:"Type"`
+	ID            string `json:"ID"`
+	AVDID         string `json:"AVDID"`
+	Title         string `json:"Title"`
+	Description   string `json:"Description,omitempty"`
+	Message       string `json:"Message,omitempty"`
+	Resolution    string `json:"Resolution,omitempty"`
+	Severity      string `json:"Severity"`
+	PrimaryURL    string `json:"PrimaryURL,omitempty"`
+	Status        string `json:"Status"`
+	CauseMetadata struct {
+		StartLine int `json:"StartLine,omitempty"`
+		EndLine   int `json:"EndLine,omitempty"`
+	} `json:"CauseMetadata,omitempty"`
+}
+
+// Secret is a single secret found in a file.
+type Secret struct {
+	RuleID    string `json:"RuleID"`
+	Category  string `json:"Category"`
+	Severity  string `json:"Severity"`
+	Title     string `json:"Title"`
+	StartLine int    `json:"StartLine"`
+	EndLine   int    `json:"EndLine"`
+	Layer     Layer  `json:"Layer,omitempty"`
+}
+
+// License is a single license found by the license scanner.
+type License struct {
+	Severity   string  `json:"Severity"`
+	Category   string  `json:"Category"`
+	PkgName    string  `json:"PkgName"`
+	FilePath   string  `json:"FilePath,omitempty"`
+	Name       string  `json:"Name"`
+	Confidence float64 `json:"Confidence,omitempty"`
+	Link       string  `json:"Link,omitempty"`
+}
+
+// ParseReport decodes a trivy JSON report.
+func ParseReport(data []byte) (*Report, error) {
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy report: %w", err)
+	}
+	return &report, nil
+}
+
+// The classes of findings reported by trivy.
+const (
+	ClassVulnerability    = "vulnerability"
+	ClassMisconfiguration = "misconfiguration"
+	ClassSecret           = "secret"
+)
+
+// Summary is a condensed view of a report, listing the number of findings
+// per severity and the most severe findings.
+type Summary struct {
+	Artifact          string         `json:"artifact"`
+	ArtifactType      string         `json:"artifactType"`
+	Vulnerabilities   map[string]int `json:"vulnerabilities"`
+	Misconfigurations map[string]int `json:"misconfigurations,omitempty"`
+	Secrets           map[string]int `json:"secrets,omitempty"`
+	Total             int            `json:"total"`
+	Fixable           int            `json:"fixable"`
+	Top               []Finding      `json:"top"`
+	Cached            bool           `json:"cached,omitempty"`
+}
+
+// Finding is a single vulnerability, failed misconfiguration check or
+// secret, as listed by the summary.
+type Finding struct {
+	Class            string `json:"class"`
+	ID               string `json:"id"`
+	Severity         string `json:"severity"`
+	Package          string `json:"package,omitempty"`
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	FixedVersion     string `json:"fixedVersion,omitempty"`
+	Title            string `json:"title,omitempty"`
+	Target           string `json:"target"`
+	Layer            string `json:"layer,omitempty"`
+	Line             int    `json:"line,omitempty"`
+}
+
+// Findings flattens the findings of all the results of the report.
+// Misconfiguration checks which passed are not included.
+func (r *Report) Findings() []Finding {
+	var findings []Finding
+	for _, result := range r.Results {
+		for _, vuln := range result.Vulnerabilities {
+			findings = append(findings, Finding{
+				Class:            ClassVulnerability,
+				ID:               vuln.VulnerabilityID,
+				Severity:         vuln.Severity,
+				Package:          vuln.PkgName,
+				InstalledVersion: vuln.InstalledVersion,
+				FixedVersion:     vuln.FixedVersion,
+				Title:            vuln.Title,
+				Target:           result.Target,
+				Layer:            vuln.Layer.DiffID,
+			})
+		}
+		for _, misconf := range result.Misconfigurations {
+			if misconf.Status != "FAIL" {
+				continue
+			}
+			findings = append(findings, Finding{
+				Class:    ClassMisconfiguration,
+				ID:       misconf.AVDID,
+				Severity: misconf.Severity,
+				Title:    misconf.Title,
+				Target:   result.Target,
+				Line:     misconf.CauseMetadata.StartLine,
+			})
+		}
+		for _, secret := range result.Secrets {
+			findings = append(findings, Finding{
+				Class:    ClassSecret,
+				ID:       secret.RuleID,
+				Severity: secret.Severity,
+				Title:    secret.Title,
+				Target:   result.Target,
+				Layer:    secret.Layer.DiffID,
+				Line:     secret.StartLine,
+			})
+		}
+	}
+	return findings
+}
+
+// Vulnerabilities returns the vulnerability findings of the report.
+func (r *Report) Vulnerabilities() []Finding {
+	var vulns []Finding
+	for _, finding := range r.Findings() {
+		if finding.Class == ClassVulnerability {
+			vulns = append(vulns, finding)
+		}
+	}
+	return vulns
+}
+
+// Summarize builds the summary of the report, listing at most top of the
+// most severe findings.
+func (r *Report) Summarize(top int) Summary {
+	summary := Summary{
+		Artifact:        r.ArtifactName,
+		ArtifactType:    r.ArtifactType,
+		Vulnerabilities: severityCounts(),
+		Top:             []Finding{},
+		Cached:          r.Cached,
+	}
+
+	findings := r.Findings()
+	for _, finding := range findings {
+		summary.Total++
+		switch finding.Class {
+		case ClassVulnerability:
+			summary.Vulnerabilities[finding.Severity]++
+			if finding.FixedVersion != "" {
+				summary.Fixable++
+			}
+		case ClassMisconfiguration:
+			if summary.Misconfigurations == nil {
+				summary.Misconfigurations = severityCounts()
+			}
+			summary.Misconfigurations[finding.Severity]++
+		case ClassSecret:
+			if summary.Secrets == nil {
+				summary.Secrets = severityCounts()
+			}
+			summary.Secrets[finding.Severity]++
+		}
+	}
+
+	SortFindings(findings)
+	if len(findings) > top {
+		findings = findings[:top]
+	}
+	summary.Top = append(summary.Top, findings...)
+	return summary
+}
+
+// severityCounts returns a map counting findings for every severity.
+func severityCounts() map[string]int {
+	counts := make(map[string]int, len(Severities))
+	for _, severity := range Severities {
+		counts[severity] = 0
+	}
+	return counts
+}
+
+// SortFindings orders findings by severity, listing the fixable ones first
+// within the same severity.
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) < severityRank(b.Severity)
+		}
+		if (a.FixedVersion != "") != (b.FixedVersion != "") {
+			return a.FixedVersion != ""
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Package < b.Package
+	})
+}
+
+// severityRank returns the position of the severity in Severities, where
+// a lower rank means a more severe finding.
+func severityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(Severities)
+}
@@ -0,0 +1,192 @@
+package trivy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// In this file, we define the SARIF 2.1.0 export of the scans, which
+// security dashboards ingest. The log is the one trivy writes, converted
+// from the JSON report of the scan so cached reports are exported without
+// scanning again, and post-processed: artifact URIs are made valid URI
+// references, and locations point to the image layer which introduced the
+// finding.
+
+// SARIFMIMEType is the MIME type of SARIF logs.
+const SARIFMIMEType = "application/sarif+json"
+
+// Convert converts the JSON report of a scan into the given format, e.g.
+// sarif, with trivy convert, which neither scans nor needs the DB.
+func Convert(ctx context.Context, report []byte, format string) ([]byte, error) {
+	reportFile, err := os.CreateTemp("", "mcp-docker-trivy-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer os.Remove(reportFile.Name())
+	_, err = reportFile.Write(report)
+	if closeErr := reportFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	convertCmd := exec.CommandContext(ctx, "trivy", "convert", "--quiet", "--format", format, reportFile.Name())
+	convertCmd.Env = config.env()
+	convertCmd.Stderr = &stderr
+	outBytes, err := convertCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert report to %s: %w \n %s", format, err, stderr.String())
+	}
+	return outBytes, nil
+}
+
+// SARIF returns the findings of the report as a SARIF 2.1.0 log, given the
+// JSON report it was parsed from.
+func SARIF(ctx context.Context, report *Report, reportBytes []byte) ([]byte, error) {
+	sarifBytes, err := Convert(ctx, reportBytes, "sarif")
+	if err != nil {
+		return nil, err
+	}
+	var log map[string]interface{}
+	if err := json.Unmarshal(sarifBytes, &log); err != nil {
+		return nil, fmt.Errorf("failed to parse SARIF log: %w", err)
+	}
+
+	layers := report.findingLayers()
+	for _, run := range objects(log["runs"]) {
+		for _, artifact := range objects(run["artifacts"]) {
+			if location, ok := artifact["location"].(map[string]interface{}); ok {
+				fixArtifactURI(location)
+			}
+		}
+		for _, result := range objects(run["results"]) {
+			ruleID, _ := result["ruleId"].(string)
+			for _, location := range objects(result["locations"]) {
+				physical, _ := location["physicalLocation"].(map[string]interface{})
+				if physical == nil {
+					continue
+				}
+				artifact, _ := physical["artifactLocation"].(map[string]interface{})
+				if artifact == nil {
+					continue
+				}
+				// Findings are looked up by the location trivy gave them,
+				// before the URI is fixed.
+				for _, key := range locationKeys(ruleID, location, physical, artifact) {
+					if layer, found := layers[key]; found {
+						location["logicalLocations"] = []interface{}{map[string]interface{}{
+							"name":               layer,
+							"fullyQualifiedName": layer,
+							"kind":               "layer",
+						}}
+						break
+					}
+				}
+				fixArtifactURI(artifact)
+			}
+		}
+	}
+
+	sarifBytes, err = json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SARIF log: %w", err)
+	}
+	return sarifBytes, nil
+}
+
+// findingLayers returns the diff IDs of the layers which introduced the
+// vulnerabilities and secrets of the report, keyed like locationKeys.
+func (r *Report) findingLayers() map[string]string {
+	layers := make(map[string]string)
+	for _, result := range r.Results {
+		for _, vuln := range result.Vulnerabilities {
+			if vuln.Layer.DiffID != "" {
+				layers[vulnKey(vuln.VulnerabilityID, vuln.PkgName+"@"+vuln.InstalledVersion)] = vuln.Layer.DiffID
+			}
+		}
+		for _, secret := range result.Secrets {
+			if secret.Layer.DiffID != "" {
+				layers[secretKey(secret.RuleID, result.Target, secret.StartLine)] = secret.Layer.DiffID
+			}
+		}
+	}
+	return layers
+}
+
+func vulnKey(ruleID, pkg string) string {
+	return "vuln\x00" + ruleID + "\x00" + pkg
+}
+
+func secretKey(ruleID, target string, line int) string {
+	return "secret\x00" + ruleID + "\x00" + strings.TrimPrefix(target, "/") + "\x00" + strconv.Itoa(line)
+}
+
+// locationKeys returns the keys the finding at the SARIF location may have.
+// trivy describes the locations of vulnerabilities as "<path>:
+// <pkg>@<version>", and those of secrets by their file and region.
+func locationKeys(ruleID string, location, physical, artifact map[string]interface{}) []string {
+	var keys []string
+	if message, ok := location["message"].(map[string]interface{}); ok {
+		if text, _ := message["text"].(string); strings.Contains(text, "@") {
+			if i := strings.LastIndex(text, ": "); i >= 0 {
+				text = text[i+2:]
+			}
+			keys = append(keys, vulnKey(ruleID, text))
+		}
+	}
+	uri, _ := artifact["uri"].(string)
+	line := 0
+	if region, ok := physical["region"].(map[string]interface{}); ok {
+		if startLine, ok := region["startLine"].(float64); ok {
+			line = int(startLine)
+		}
+	}
+	return append(keys, secretKey(ruleID, uri, line))
+}
+
+// fixArtifactURI turns the URI of the artifact location into a valid URI
+// reference. trivy sets it to the scan target, which for images and some
+// files isn't one, e.g. it holds spaces or a colon in its first segment.
+func fixArtifactURI(artifact map[string]interface{}) {
+	uri, ok := artifact["uri"].(string)
+	if !ok || uri == "" {
+		return
+	}
+	artifact["uri"] = artifactURI(uri)
+}
+
+// artifactURI returns the path as a relative URI reference, keeping the
+// URIs of files and remote locations as they are.
+func artifactURI(path string) string {
+	if u, err := url.Parse(path); err == nil {
+		switch {
+		case u.Scheme == "file" || (u.Scheme != "" && u.Host != ""):
+			return path
+		case u.Scheme == "" && u.String() == path:
+			return path
+		}
+	}
+	return (&url.URL{Path: strings.TrimPrefix(path, "/")}).String()
+}
+
+// objects returns the JSON objects of the JSON array, skipping the values
+// of other types.
+func objects(value interface{}) []map[string]interface{} {
+	array, _ := value.([]interface{})
+	result := make([]map[string]interface{}, 0, len(array))
+	for _, item := range array {
+		if object, ok := item.(map[string]interface{}); ok {
+			result = append(result, object)
+		}
+	}
+	return result
+}
@@ -0,0 +1,408 @@
+package trivy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-docker/internal/progress"
+	"github.com/mark3labs/mcp-docker/internal/workspace"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// In this file, we define the runner shared by all the trivy scan tools,
+// along with the tool options they have in common.
+
+// defaultTop is the default number of findings listed by the summary.
+const defaultTop = 10
+
+// ScanOptions are the options shared by the trivy scan commands.
+type ScanOptions struct {
+	Severities    []string
+	IgnoreUnfixed bool
+	Scanners      []string
+	PkgTypes      []string
+	// ListAllPackages lists every package in the report, not only the
+	// vulnerable ones. It's only supported by the JSON format.
+	ListAllPackages bool
+	// Refresh scans the image again, even when a result is cached. It
+	// doesn't change the report, so it's not part of the cache key.
+	Refresh bool
+}
+
+// args returns the trivy flags of the scan options.
+func (o ScanOptions) args() []string {
+	var args []string
+	if len(o.Severities) > 0 {
+		args = append(args, "--severity", strings.Join(o.Severities, ","))
+	}
+	if o.IgnoreUnfixed {
+		args = append(args, "--ignore-unfixed")
+	}
+	if len(o.Scanners) > 0 {
+		args = append(args, "--scanners", strings.Join(o.Scanners, ","))
+	}
+	if len(o.PkgTypes) > 0 {
+		args = append(args, "--pkg-types", strings.Join(o.PkgTypes, ","))
+	}
+	if o.ListAllPackages {
+		args = append(args, "--list-all-pkgs")
+	}
+	return args
+}
+
+// Scan runs the trivy command, e.g. image or fs, against the target and
+// returns the report in the given format.
+func Scan(ctx context.Context, command, target, format string, opts ScanOptions) ([]byte, error) {
+	// The logs are only kept when progress is reported, as they tell the
+	// stages of the scan.
+	reporter := progress.FromContext(ctx)
+	args := []string{command, "--format", format}
+	if reporter == nil {
+		args = append(args, "--quiet")
+	}
+	args = append(args, config.args(command, opts.Scanners)...)
+	args = append(args, opts.args()...)
+	args = append(args, target)
+
+	var stderr bytes.Buffer
+	scanCmd := exec.CommandContext(ctx, "trivy", args...)
+	scanCmd.Env = config.env()
+	scanCmd.Stderr = &stderr
+	if reporter != nil {
+		reporter.Stage(fmt.Sprintf("Scanning %s with trivy", target))
+		scanCmd.Stderr = io.MultiWriter(&stderr, &stageWriter{reporter: reporter})
+	}
+	outBytes, err := scanCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w \n %s", target, err, stderr.String())
+	}
+	return outBytes, nil
+}
+
+// logLine matches the log lines of trivy, capturing their message without
+// the name of the logger, e.g. "2024-05-01T10:00:00Z	INFO	[vulndb] Need
+// to update DB".
+var logLine = regexp.MustCompile(`^\S+\s+(?:INFO|WARN)\s+(?:\[[^\]]*\]\s+)?(.+)$`)
+
+// stageWriter reports the info and warning logs of trivy, which mark the
+// stages of a scan, e.g. the vulnerability DB download or the detection of
+// the OS, as progress.
+type stageWriter struct {
+	reporter *progress.Reporter
+	line     []byte
+}
+
+func (w *stageWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b != '\n' && b != '\r' {
+			w.line = append(w.line, b)
+			continue
+		}
+		if match := logLine.FindSubmatch(w.line); match != nil {
+			// Fields are tab separated, e.g. Detected OS	family="alpine".
+			w.reporter.Stage(strings.Join(strings.Fields(string(match[1])), " "))
+		}
+		w.line = w.line[:0]
+	}
+	return len(p), nil
+}
+
+// ScanReport runs the trivy command against the target and parses the
+// resulting JSON report. Image scans are served from the result cache,
+// unless a refresh is requested.
+func ScanReport(ctx context.Context, command, target string, opts ScanOptions) (*Report, []byte, error) {
+	key, cacheable := cacheKey(ctx, command, target, opts)
+	if cacheable && !opts.Refresh {
+		if outBytes, found := results.get(key, time.Now()); found {
+			report, err := ParseReport(outBytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			report.Cached = true
+			return report, outBytes, nil
+		}
+	}
+
+	outBytes, err := Scan(ctx, command, target, "json", opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	report, err := ParseReport(outBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cacheable {
+		results.put(key, outBytes, time.Now(), config.ResultTTL)
+	}
+	return report, outBytes, nil
+}
+
+// scanTool describes a trivy scan tool: the trivy command it runs, the
+// argument naming the scan target and the options it supports.
+type scanTool struct {
+	command   string
+	targetArg string
+	vulnScan  bool
+	// prepare, when set, turns the target argument into the target
+	// scanned by trivy, returning a function releasing it afterwards.
+	prepare func(ctx context.Context, target string) (string, func(), error)
+}
+
+// severityOption is the tool option filtering findings by severity.
+func severityOption() mcp.ToolOption {
+	return mcp.WithArray("severity",
+		mcp.Description("Only report findings of these severities"),
+		mcp.Items(map[string]interface{}{
+			"type": "string",
+			"enum": []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"},
+		}),
+	)
+}
+
+// ignoreUnfixedOption is the tool option hiding vulnerabilities without a fix.
+func ignoreUnfixedOption() mcp.ToolOption {
+	return mcp.WithBoolean("ignore-unfixed",
+		mcp.Description("Only report vulnerabilities which have a fix available"),
+		mcp.DefaultBool(false),
+	)
+}
+
+// scannersOption is the tool option selecting the scanners to run.
+func scannersOption() mcp.ToolOption {
+	return mcp.WithArray("scanners",
+		mcp.Description("The scanners to run, trivy defaults to vuln and secret"),
+		mcp.Items(map[string]interface{}{
+			"type": "string",
+			"enum": []string{"vuln", "secret", "misconfig", "license"},
+		}),
+	)
+}
+
+// pkgTypesOption is the tool option selecting the package types to scan.
+func pkgTypesOption() mcp.ToolOption {
+	return mcp.WithArray("pkg-types",
+		mcp.Description("The package types to scan for vulnerabilities"),
+		mcp.Items(map[string]interface{}{
+			"type": "string",
+			"enum": []string{"os", "library"},
+		}),
+	)
+}
+
+// formatOption is the tool option selecting the output format of a scan,
+// where summary is always supported and is the default.
+func formatOption(formats ...string) mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description("The output format, summary returns the counts per severity and the most severe findings"),
+		mcp.Enum(append([]string{"summary"}, formats...)...),
+		mcp.DefaultString("summary"),
+	)
+}
+
+// topOption is the tool option limiting the findings listed by the summary.
+func topOption() mcp.ToolOption {
+	return mcp.WithNumber("top",
+		mcp.Description("The number of most severe findings listed by the summary"),
+		mcp.DefaultNumber(defaultTop),
+		mcp.Min(0),
+	)
+}
+
+// fullReportOption is the tool option returning the full JSON report
+// alongside the summary.
+func fullReportOption() mcp.ToolOption {
+	return mcp.WithBoolean("full-report",
+		mcp.Description("Return the full JSON report alongside the summary"),
+		mcp.DefaultBool(false),
+	)
+}
+
+// sarifOutputOption is the tool option writing the findings as a SARIF
+// log to a file.
+func sarifOutputOption() mcp.ToolOption {
+	return mcp.WithString("sarif-output",
+		mcp.Description("The path of a file, relative to the workspace, to write the findings to as a SARIF 2.1.0 log"),
+	)
+}
+
+// sarifResourceOption is the tool option returning the findings as a
+// SARIF log embedded in the result.
+func sarifResourceOption() mcp.ToolOption {
+	return mcp.WithBoolean("sarif-resource",
+		mcp.Description("Return the findings as a SARIF 2.1.0 log embedded resource"),
+		mcp.DefaultBool(false),
+	)
+}
+
+// refreshOption is the tool option bypassing the cached result of a
+// previous scan of the image.
+func refreshOption() mcp.ToolOption {
+	return mcp.WithBoolean("refresh",
+		mcp.Description("Scan the image again instead of returning the cached result of a previous scan"),
+		mcp.DefaultBool(false),
+	)
+}
+
+// parseScanOptions reads the scan options supported by the tool.
+func (t scanTool) parseScanOptions(req mcp.CallToolRequest) (ScanOptions, error) {
+	var opts ScanOptions
+	var err error
+	if opts.Severities, err = params.StringSlice(req, "severity"); err != nil {
+		return opts, err
+	}
+	if opts.Refresh, err = params.Bool(req, "refresh", false); err != nil {
+		return opts, err
+	}
+	if !t.vulnScan {
+		return opts, nil
+	}
+	if opts.IgnoreUnfixed, err = params.Bool(req, "ignore-unfixed", false); err != nil {
+		return opts, err
+	}
+	if opts.Scanners, err = params.StringSlice(req, "scanners"); err != nil {
+		return opts, err
+	}
+	if opts.PkgTypes, err = params.StringSlice(req, "pkg-types"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// handler returns the handler function of the scan tool, which runs
+// trivy and returns either the summary of the report or the raw report
+// in the requested format.
+func (t scanTool) handler() server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name, err := params.String(req, t.targetArg)
+		if err != nil {
+			return nil, err
+		}
+		opts, err := t.parseScanOptions(req)
+		if err != nil {
+			return nil, err
+		}
+		format, err := params.OptionalString(req, "format", "summary")
+		if err != nil {
+			return nil, err
+		}
+		top, err := params.Int(req, "top", defaultTop)
+		if err != nil {
+			return nil, err
+		}
+		fullReport, err := params.Bool(req, "full-report", false)
+		if err != nil {
+			return nil, err
+		}
+		sarifOutput, err := params.OptionalString(req, "sarif-output", "")
+		if err != nil {
+			return nil, err
+		}
+		sarifResource, err := params.Bool(req, "sarif-resource", false)
+		if err != nil {
+			return nil, err
+		}
+		exportSARIF := sarifOutput != "" || sarifResource
+		if exportSARIF && format != "summary" && format != "json" && format != "sarif" {
+			return nil, fmt.Errorf("%w: SARIF export requires the summary, json or sarif format, got %q", params.ErrInvalidParams, format)
+		}
+
+		target := name
+		if t.prepare != nil {
+			prepared, release, err := t.prepare(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+			target = prepared
+		}
+
+		// SARIF logs are converted from the JSON report, as the exports are,
+		// so both are the same.
+		if format != "summary" && format != "json" && format != "sarif" {
+			outBytes, err := Scan(ctx, t.command, target, format, opts)
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(string(outBytes)), nil
+		}
+
+		report, outBytes, err := ScanReport(ctx, t.command, target, opts)
+		if err != nil {
+			return nil, err
+		}
+		report.ArtifactName = name
+
+		var sarifBytes []byte
+		if format == "sarif" || exportSARIF {
+			if sarifBytes, err = SARIF(ctx, report, outBytes); err != nil {
+				return nil, err
+			}
+		}
+
+		var result *mcp.CallToolResult
+		switch format {
+		case "json":
+			result = mcp.NewToolResultText(string(outBytes))
+		case "sarif":
+			result = mcp.NewToolResultText(string(sarifBytes))
+		default:
+			result, err = summaryResult(report.Summarize(top), outBytes, fullReport)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if exportSARIF {
+			if err := attachSARIF(result, report.ArtifactName, sarifBytes, sarifOutput, sarifResource); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+}
+
+// attachSARIF writes the SARIF log of the artifact to the output path in
+// the workspace, when given, and embeds it into the result, when requested
+// or written.
+func attachSARIF(result *mcp.CallToolResult, artifact string, sarifBytes []byte, output string, embed bool) error {
+	if output != "" {
+		rel, err := workspace.WriteFile(output, sarifBytes)
+		if err != nil {
+			return fmt.Errorf("failed to write SARIF log: %w", err)
+		}
+		result.Content = append(result.Content, workspace.Resource(rel, SARIFMIMEType, sarifBytes)...)
+		return nil
+	}
+	if embed {
+		result.Content = append(result.Content, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      "trivy://sarif/" + url.PathEscape(artifact),
+			MIMEType: SARIFMIMEType,
+			Text:     string(sarifBytes),
+		}))
+	}
+	return nil
+}
+
+// summaryResult returns the summary as JSON text, followed by the full
+// JSON report when requested.
+func summaryResult(summary Summary, report []byte, fullReport bool) (*mcp.CallToolResult, error) {
+	summaryBytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode scan summary: %w", err)
+	}
+
+	result := mcp.NewToolResultText(string(summaryBytes))
+	if fullReport {
+		result.Content = append(result.Content, mcp.NewTextContent(string(report)))
+	}
+	return result, nil
+}
@@ -0,0 +1,273 @@
+// Package workspace confines the files written by the tools, e.g. SBOMs
+// and SARIF logs, to a directory configured at serve time, and exposes
+// them as MCP resources.
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// URIPrefix is the prefix of the URIs of the workspace files.
+const URIPrefix = "workspace://"
+
+// ErrOutsideWorkspace is returned for paths escaping the workspace.
+var ErrOutsideWorkspace = errors.New("path is outside of the workspace")
+
+// dir is the absolute path of the workspace directory, with its symlinks
+// resolved, and configuredDir the absolute path it was configured with,
+// which absolute paths may be given through too.
+var dir, configuredDir string
+
+// DefaultDir returns the default workspace directory.
+func DefaultDir() string {
+	return filepath.Join(os.TempDir(), "mcp-docker")
+}
+
+// Configure sets the workspace directory, creating it when missing. It's
+// meant to be called once, before the tools are added to the server.
+func Configure(workspaceDir string) error {
+	abs, err := filepath.Abs(workspaceDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve workspace: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+	// The workspace itself may be reached through a symlink, e.g. /tmp on
+	// macOS, which must not be mistaken for an escape.
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return fmt.Errorf("failed to resolve workspace: %w", err)
+	}
+	dir, configuredDir = resolved, abs
+	return nil
+}
+
+// Dir returns the workspace directory.
+func Dir() string {
+	if dir == "" {
+		return DefaultDir()
+	}
+	return dir
+}
+
+// Resolve turns a path given by a client into a slash separated path
+// relative to the workspace. Relative paths are relative to the workspace,
+// and absolute paths must point inside it, either as configured or with
+// its symlinks resolved.
+func Resolve(name string) (string, error) {
+	rel := filepath.Clean(name)
+	if !filepath.IsAbs(rel) {
+		if !inside(rel) {
+			return "", fmt.Errorf("%w: %w: %s", params.ErrInvalidParams, ErrOutsideWorkspace, name)
+		}
+		return filepath.ToSlash(rel), nil
+	}
+	for _, base := range []string{Dir(), configuredDir} {
+		if base == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(base, filepath.Clean(name)); err == nil && inside(rel) {
+			return filepath.ToSlash(rel), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %w: %s", params.ErrInvalidParams, ErrOutsideWorkspace, name)
+}
+
+// inside reports whether the relative path stays inside the directory it's
+// relative to, without being the directory itself.
+func inside(rel string) bool {
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) &&
+		!filepath.IsAbs(rel) && filepath.VolumeName(rel) == ""
+}
+
+// URI returns the URI of the workspace file at the relative path.
+func URI(rel string) string {
+	return URIPrefix + rel
+}
+
+// openRoot opens the workspace as a root, through which symlinks can't
+// lead outside of it.
+func openRoot() (*os.Root, error) {
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	root, err := os.OpenRoot(Dir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workspace: %w", err)
+	}
+	return root, nil
+}
+
+// WriteFile writes the data to the file at the path, which must resolve
+// inside the workspace, creating its parent directories. It returns the
+// path of the file relative to the workspace.
+func WriteFile(name string, data []byte) (string, error) {
+	rel, err := Resolve(name)
+	if err != nil {
+		return "", err
+	}
+	root, err := openRoot()
+	if err != nil {
+		return "", err
+	}
+	defer root.Close()
+
+	if err := MkdirAll(root, path.Dir(rel)); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+	file, err := root.OpenFile(rel, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+	return rel, nil
+}
+
+// MkdirAll creates the directory name, a slash separated path, in root
+// along with its parents, like os.MkdirAll does outside of a root.
+func MkdirAll(root *os.Root, name string) error {
+	if name == "." || name == "" {
+		return nil
+	}
+	current := ""
+	for _, part := range strings.Split(name, "/") {
+		current = path.Join(current, part)
+		err := root.Mkdir(current, 0o755)
+		if err != nil && !os.IsExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile reads the file at the path relative to the workspace.
+func ReadFile(rel string) ([]byte, error) {
+	rel, err := Resolve(rel)
+	if err != nil {
+		return nil, err
+	}
+	root, err := openRoot()
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	file, err := root.Open(rel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	return data, nil
+}
+
+// RemoveOlder removes the files of the directory, relative to the
+// workspace, last modified before the given time. A missing directory has
+// nothing to remove.
+func RemoveOlder(name string, before time.Time) error {
+	rel, err := Resolve(name)
+	if err != nil {
+		return err
+	}
+	root, err := openRoot()
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	dirFile, err := root.Open(rel)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to open %s: %w", rel, err)
+	}
+	entries, err := dirFile.ReadDir(-1)
+	dirFile.Close()
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", rel, err)
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || !info.ModTime().Before(before) {
+			continue
+		}
+		if err := root.Remove(path.Join(rel, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", path.Join(rel, entry.Name()), err)
+		}
+	}
+	return nil
+}
+
+// guessMIMEType guesses the MIME type of the file from its extension.
+func guessMIMEType(rel string) string {
+	if strings.HasSuffix(rel, ".sarif") || strings.HasSuffix(rel, ".sarif.json") {
+		return "application/sarif+json"
+	}
+	if mimeType := mime.TypeByExtension(path.Ext(rel)); mimeType != "" {
+		return mimeType
+	}
+	return "text/plain"
+}
+
+// Resource returns the written file at the path relative to the workspace
+// as an embedded resource, along with a note of where it was written.
+func Resource(rel, mimeType string, data []byte) []mcp.Content {
+	return []mcp.Content{
+		mcp.NewTextContent(fmt.Sprintf("Written to %s in the workspace, available as %s", rel, URI(rel))),
+		mcp.NewEmbeddedResource(mcp.TextResourceContents{
+			URI:      URI(rel),
+			MIMEType: mimeType,
+			Text:     string(data),
+		}),
+	}
+}
+
+// FileTemplate is the resource template of the workspace files.
+var FileTemplate = mcp.NewResourceTemplate(URIPrefix+"{+path}", "Workspace file",
+	mcp.WithTemplateDescription("A file written by a tool to the workspace, e.g. an SBOM or a SARIF log"),
+)
+
+// FileHandler is the handler function that reads workspace files.
+func FileHandler(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	rel := strings.TrimPrefix(req.Params.URI, URIPrefix)
+	data, err := ReadFile(rel)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      req.Params.URI,
+		MIMEType: guessMIMEType(rel),
+		Text:     string(data),
+	}}, nil
+}
+
+// WithFileResources adds the workspace file resources to the MCP server
+func WithFileResources(s *server.MCPServer) *server.MCPServer {
+	s.AddResourceTemplate(FileTemplate, FileHandler)
+	return s
+}
@@ -0,0 +1,172 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-docker/internal/params"
+)
+
+// configure sets up a workspace in a new temporary directory, next to a
+// directory outside of it holding a secret file, and returns both.
+func configure(t *testing.T) (string, string) {
+	t.Helper()
+	base := t.TempDir()
+	ws := filepath.Join(base, "ws")
+	outside := filepath.Join(base, "outside")
+	if err := os.Mkdir(outside, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Configure(ws); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	t.Cleanup(func() { dir, configuredDir = "", "" })
+	return Dir(), outside
+}
+
+func TestResolve(t *testing.T) {
+	ws, outside := configure(t)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"sbom.json", "sbom.json"},
+		{"out/sbom.json", "out/sbom.json"},
+		{"./out/../sbom.json", "sbom.json"},
+		{filepath.Join(ws, "out", "sbom.json"), "out/sbom.json"},
+		{"..", ""},
+		{".", ""},
+		{"", ""},
+		{"../outside/secret", ""},
+		{"out/../../outside/secret", ""},
+		{filepath.Join(outside, "secret"), ""},
+		{"/etc/passwd", ""},
+		{ws, ""},
+		{ws + "-other/x", ""},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.name)
+		if tt.want == "" {
+			if !errors.Is(err, ErrOutsideWorkspace) || !errors.Is(err, params.ErrInvalidParams) {
+				t.Errorf("Resolve(%q) = %q, %v, want an invalid params error", tt.name, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolveThroughSymlink(t *testing.T) {
+	base := t.TempDir()
+	realDir := filepath.Join(base, "real")
+	if err := os.Mkdir(realDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(base, "link")
+	if err := os.Symlink(realDir, link); err != nil {
+		t.Fatal(err)
+	}
+	if err := Configure(filepath.Join(link, "ws")); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	t.Cleanup(func() { dir, configuredDir = "", "" })
+
+	resolvedRealDir, err := filepath.EvalSymlinks(realDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		filepath.Join(link, "ws", "x.json"),
+		filepath.Join(resolvedRealDir, "ws", "x.json"),
+	} {
+		if got, err := Resolve(name); err != nil || got != "x.json" {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", name, got, err, "x.json")
+		}
+	}
+	if got, err := Resolve(filepath.Join(link, "x.json")); err == nil {
+		t.Errorf("Resolve outside the workspace = %q, want an error", got)
+	}
+}
+
+func TestWriteReadFile(t *testing.T) {
+	ws, _ := configure(t)
+
+	rel, err := WriteFile("reports/scan.sarif", []byte("log"))
+	if err != nil || rel != "reports/scan.sarif" {
+		t.Fatalf("WriteFile = %q, %v", rel, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(ws, "reports", "scan.sarif")); err != nil || string(data) != "log" {
+		t.Errorf("written file = %q, %v, want %q", data, err, "log")
+	}
+	if data, err := ReadFile("reports/scan.sarif"); err != nil || string(data) != "log" {
+		t.Errorf("ReadFile = %q, %v, want %q", data, err, "log")
+	}
+	if _, err := ReadFile("missing.json"); err == nil {
+		t.Errorf("ReadFile of a missing file succeeded")
+	}
+}
+
+func TestSymlinksOutOfWorkspace(t *testing.T) {
+	ws, outside := configure(t)
+	if err := os.Symlink(outside, filepath.Join(ws, "out")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret"), filepath.Join(ws, "secret")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../outside/secret", filepath.Join(ws, "relative")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"out/secret", "secret", "relative"} {
+		if data, err := ReadFile(name); err == nil {
+			t.Errorf("ReadFile(%q) = %q, want an error", name, data)
+		}
+	}
+	for _, name := range []string{"out/planted", "out/new/planted", "secret"} {
+		if _, err := WriteFile(name, []byte("x")); err == nil {
+			t.Errorf("WriteFile(%q) succeeded, want an error", name)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(outside, "planted")); !os.IsNotExist(err) {
+		t.Errorf("file planted outside of the workspace")
+	}
+	if data, err := os.ReadFile(filepath.Join(outside, "secret")); err != nil || string(data) != "secret" {
+		t.Errorf("outside file changed: %q, %v", data, err)
+	}
+}
+
+func TestRemoveOlder(t *testing.T) {
+	ws, _ := configure(t)
+	for _, name := range []string{"outputs/old.txt", "outputs/new.txt"} {
+		if _, err := WriteFile(name, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(ws, "outputs", "old.txt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveOlder("outputs", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("RemoveOlder failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(ws, "outputs", "old.txt")); !os.IsNotExist(err) {
+		t.Errorf("old output kept")
+	}
+	if _, err := os.Stat(filepath.Join(ws, "outputs", "new.txt")); err != nil {
+		t.Errorf("new output removed: %v", err)
+	}
+	if err := RemoveOlder("missing", time.Now()); err != nil {
+		t.Errorf("RemoveOlder of a missing directory failed: %v", err)
+	}
+}